@@ -0,0 +1,607 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ishanbagra18/ecommerce-using-go/database"
+	"github.com/ishanbagra18/ecommerce-using-go/helpers"
+	"github.com/ishanbagra18/ecommerce-using-go/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var albumCollection *mongo.Collection
+
+func InitAlbumController() {
+	albumCollection = database.OpenCollection(database.Client, "albums")
+	log.Println("✔ Album collection initialized")
+
+	database.CreateIndexes(albumCollection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "album_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "tracks.song_id", Value: 1}}},
+		{Keys: bson.D{{Key: "artist_ids", Value: 1}, {Key: "release_date", Value: -1}}},
+		{
+			Keys:    bson.D{{Key: "title", Value: "text"}, {Key: "artist", Value: "text"}},
+			Options: options.Index().SetName("album_text").SetDefaultLanguage("none").SetWeights(bson.D{{Key: "title", Value: 10}, {Key: "artist", Value: 4}}),
+		},
+	})
+}
+
+// canManageAlbum lets the album's creator and admins edit it
+func canManageAlbum(c *gin.Context, album models.Album) bool {
+	return c.GetString("user_type") == "ADMIN" || album.CreatedBy == c.GetString("user_id")
+}
+
+// parseReleaseDate accepts RFC3339 or yyyy-mm-dd
+func parseReleaseDate(value string) (*time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, true
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return &t, true
+	}
+	return nil, false
+}
+
+func sortAlbumTracks(tracks []models.AlbumTrack) {
+	sort.SliceStable(tracks, func(i, j int) bool {
+		if tracks[i].DiscNumber != tracks[j].DiscNumber {
+			return tracks[i].DiscNumber < tracks[j].DiscNumber
+		}
+		return tracks[i].TrackNumber < tracks[j].TrackNumber
+	})
+}
+
+// GetAlbums lists albums, newest first, optionally for one artist (?artist_id=)
+func GetAlbums() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		// Empty albums are still being put together
+		filter := bson.M{"tracks.0": bson.M{"$exists": true}}
+		if artistID := c.Query("artist_id"); artistID != "" {
+			filter["artist_ids"] = artistID
+		}
+
+		pipeline := mongo.Pipeline{{{Key: "$match", Value: filter}}}
+		pipeline = append(pipeline, visibleAlbumStages(viewerFromContext(c))...)
+		pipeline = append(pipeline,
+			bson.D{{Key: "$sort", Value: bson.D{{Key: "release_date", Value: -1}, {Key: "created_at", Value: -1}}}},
+			bson.D{{Key: "$limit", Value: 100}},
+		)
+
+		cursor, err := albumCollection.Aggregate(ctx, pipeline)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch albums"})
+			return
+		}
+		defer cursor.Close(ctx)
+
+		var albums []models.Album
+		if err := cursor.All(ctx, &albums); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse albums"})
+			return
+		}
+		if albums == nil {
+			albums = []models.Album{}
+		}
+
+		c.JSON(http.StatusOK, gin.H{"albums": albums, "count": len(albums)})
+	}
+}
+
+// visibleAlbumStages keeps albums with at least one track the viewer can see,
+// so pending and embargoed releases don't show up before their songs do.
+// The join is on localField/foreignField so it uses the song_id index.
+func visibleAlbumStages(viewer songViewer) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$lookup", Value: bson.M{
+			"from":         "songs",
+			"localField":   "tracks.song_id",
+			"foreignField": "song_id",
+			"pipeline": bson.A{
+				bson.M{"$match": viewer.songFilter(bson.M{})},
+				bson.M{"$limit": 1},
+				bson.M{"$project": bson.M{"_id": 1}},
+			},
+			"as": "visible_tracks",
+		}}},
+		{{Key: "$match", Value: bson.M{"visible_tracks.0": bson.M{"$exists": true}}}},
+		{{Key: "$project", Value: bson.M{"visible_tracks": 0}}},
+	}
+}
+
+// GetAlbumByID returns an album with its tracks in disc and track order.
+// Tracks the viewer can't see yet (pending, embargoed...) are left out.
+func GetAlbumByID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		albumID := c.Param("album_id")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var album models.Album
+		if err := albumCollection.FindOne(ctx, bson.M{"album_id": albumID}).Decode(&album); err != nil {
+			if err == mongo.ErrNoDocuments {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Album not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch album"})
+			return
+		}
+
+		ids := make([]string, 0, len(album.Tracks))
+		for _, track := range album.Tracks {
+			ids = append(ids, track.SongID)
+		}
+
+		cursor, err := songcollection.Find(ctx,
+			publicSongFilter(c, bson.M{"song_id": bson.M{"$in": ids}}),
+			options.Find().SetProjection(bson.M{"waveform": 0, "user_play_counts": 0}),
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tracks"})
+			return
+		}
+
+		var songs []models.Song
+		if err := cursor.All(ctx, &songs); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse tracks"})
+			return
+		}
+		songsByID := make(map[string]models.Song, len(songs))
+		for _, song := range songs {
+			songsByID[song.SongID] = song
+		}
+
+		type albumTrack struct {
+			models.AlbumTrack
+			Song models.Song `json:"song"`
+		}
+		tracks := make([]albumTrack, 0, len(album.Tracks))
+		totalDuration := 0
+		for _, track := range album.Tracks {
+			if song, ok := songsByID[track.SongID]; ok {
+				tracks = append(tracks, albumTrack{AlbumTrack: track, Song: song})
+				totalDuration += song.Duration
+			}
+		}
+		// An album nothing of which is out yet stays hidden, like in GetAlbums
+		if len(tracks) == 0 && !canManageAlbum(c, album) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Album not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"album":          album,
+			"tracks":         tracks,
+			"total_duration": totalDuration,
+		})
+	}
+}
+
+// CreateAlbum creates an empty album; tracks are added with SetAlbumTracks
+// or by uploading songs with album_id.
+//
+//	POST /albums (multipart) title, artist, artist_ids (comma-separated), type, release_date, cover_file
+func CreateAlbum() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("user_id")
+
+		if err := c.Request.ParseMultipartForm(20 << 20); err != nil && err != http.ErrNotMultipart {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form data"})
+			return
+		}
+
+		title := strings.TrimSpace(c.PostForm("title"))
+		if title == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "title is required"})
+			return
+		}
+
+		albumType := models.AlbumType(strings.ToLower(c.DefaultPostForm("type", string(models.AlbumTypeAlbum))))
+		if !models.IsValidAlbumType(albumType) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid type, use one of: album | single | ep"})
+			return
+		}
+
+		now := time.Now()
+		album := models.Album{
+			ID:        primitive.NewObjectID(),
+			Title:     &title,
+			ArtistIDs: formList(c, "artist_ids"),
+			Type:      albumType,
+			Tracks:    []models.AlbumTrack{},
+			CreatedBy: userID,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		album.AlbumID = album.ID.Hex()
+
+		if artist := strings.TrimSpace(c.PostForm("artist")); artist != "" {
+			album.Artist = &artist
+		}
+
+		if value := c.PostForm("release_date"); value != "" {
+			releaseDate, ok := parseReleaseDate(value)
+			if !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "release_date must be RFC3339 or yyyy-mm-dd"})
+				return
+			}
+			album.ReleaseDate = releaseDate
+		}
+
+		if coverFile, coverHeader, err := c.Request.FormFile("cover_file"); err == nil {
+			defer coverFile.Close()
+			coverURL, err := helpers.UploadFile(coverFile, coverHeader, "album_covers")
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload cover"})
+				return
+			}
+			album.CoverURL = &coverURL
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if _, err := albumCollection.InsertOne(ctx, album); err != nil {
+			log.Println("❌ Failed to create album:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create album"})
+			return
+		}
+
+		log.Printf("✅ Album %s created\n", album.AlbumID)
+		c.JSON(http.StatusCreated, gin.H{
+			"message": "Album created successfully",
+			"album":   album,
+		})
+	}
+}
+
+// UpdateAlbum changes album details (creator or admin). A new title is copied
+// onto the album's songs.
+func UpdateAlbum() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		albumID := c.Param("album_id")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		var album models.Album
+		if err := albumCollection.FindOne(ctx, bson.M{"album_id": albumID}).Decode(&album); err != nil {
+			if err == mongo.ErrNoDocuments {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Album not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch album"})
+			return
+		}
+
+		if !canManageAlbum(c, album) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to update this album"})
+			return
+		}
+
+		if err := c.Request.ParseMultipartForm(20 << 20); err != nil && err != http.ErrNotMultipart {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form data"})
+			return
+		}
+
+		updateFields := bson.M{}
+
+		if value, ok := c.GetPostForm("title"); ok {
+			title := strings.TrimSpace(value)
+			if title == "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "title can't be empty"})
+				return
+			}
+			updateFields["title"] = title
+		}
+		if value, ok := c.GetPostForm("artist"); ok {
+			updateFields["artist"] = strings.TrimSpace(value)
+		}
+		if _, ok := c.GetPostForm("artist_ids"); ok {
+			updateFields["artist_ids"] = formList(c, "artist_ids")
+		}
+		if value, ok := c.GetPostForm("type"); ok {
+			albumType := models.AlbumType(strings.ToLower(value))
+			if !models.IsValidAlbumType(albumType) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid type, use one of: album | single | ep"})
+				return
+			}
+			updateFields["type"] = albumType
+		}
+		if value, ok := c.GetPostForm("release_date"); ok {
+			var releaseDate *time.Time
+			if value != "" {
+				parsed, ok := parseReleaseDate(value)
+				if !ok {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "release_date must be RFC3339 or yyyy-mm-dd"})
+					return
+				}
+				releaseDate = parsed
+			}
+			updateFields["release_date"] = releaseDate
+		}
+
+		var oldCoverURL *string
+		if coverFile, coverHeader, err := c.Request.FormFile("cover_file"); err == nil {
+			defer coverFile.Close()
+			coverURL, err := helpers.UploadFile(coverFile, coverHeader, "album_covers")
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload cover"})
+				return
+			}
+			updateFields["cover_url"] = coverURL
+			oldCoverURL = album.CoverURL
+		}
+
+		if len(updateFields) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "No valid fields to update"})
+			return
+		}
+		updateFields["updated_at"] = time.Now()
+
+		var updated models.Album
+		err := albumCollection.FindOneAndUpdate(ctx,
+			bson.M{"album_id": albumID},
+			bson.M{"$set": updateFields},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&updated)
+		if err != nil {
+			log.Println("❌ Failed to update album:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update album"})
+			return
+		}
+
+		if title, ok := updateFields["title"]; ok {
+			if _, err := songcollection.UpdateMany(ctx,
+				bson.M{"album_id": albumID},
+				bson.M{"$set": bson.M{"album": title, "updated_at": time.Now()}},
+			); err != nil {
+				log.Printf("⚠️ Failed to rename album on songs of %s: %v\n", albumID, err)
+			}
+			reindexSongs(ctx, bson.M{"album_id": albumID})
+		}
+
+		if oldCoverURL != nil && *oldCoverURL != "" {
+			if err := deleteUnusedFile(ctx, *oldCoverURL); err != nil {
+				log.Printf("⚠️ Failed to delete old cover of album %s: %v\n", albumID, err)
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Album updated successfully",
+			"album":   updated,
+		})
+	}
+}
+
+// DeleteAlbum removes an album (creator or admin). Its songs stay in the
+// catalogue as standalone tracks.
+func DeleteAlbum() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		albumID := c.Param("album_id")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		var album models.Album
+		if err := albumCollection.FindOne(ctx, bson.M{"album_id": albumID}).Decode(&album); err != nil {
+			if err == mongo.ErrNoDocuments {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Album not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch album"})
+			return
+		}
+
+		if !canManageAlbum(c, album) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to delete this album"})
+			return
+		}
+
+		if _, err := albumCollection.DeleteOne(ctx, bson.M{"album_id": albumID}); err != nil {
+			log.Println("❌ Failed to delete album:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete album"})
+			return
+		}
+
+		if _, err := songcollection.UpdateMany(ctx,
+			bson.M{"album_id": albumID},
+			bson.M{"$set": bson.M{"album": "", "updated_at": time.Now()}, "$unset": bson.M{"album_id": ""}},
+		); err != nil {
+			log.Printf("⚠️ Album %s deleted but its songs were not unlinked: %v\n", albumID, err)
+		}
+		reindexSongs(ctx, bson.M{"song_id": bson.M{"$in": albumSongIDs(album)}})
+
+		if album.CoverURL != nil && *album.CoverURL != "" {
+			if err := deleteUnusedFile(ctx, *album.CoverURL); err != nil {
+				log.Printf("⚠️ Failed to delete cover of album %s: %v\n", albumID, err)
+			}
+		}
+
+		log.Printf("✅ Album %s deleted\n", albumID)
+		c.JSON(http.StatusOK, gin.H{"message": "Album deleted successfully"})
+	}
+}
+
+// SetAlbumTracks replaces the album's track list (creator or admin). Songs must
+// be manageable by the caller and not already on another album. Missing disc
+// numbers default to 1 and missing track numbers to the position in the list.
+//
+//	PUT /albums/:album_id/tracks {"tracks": [{"song_id": "...", "disc_number": 1, "track_number": 1}]}
+func SetAlbumTracks() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		albumID := c.Param("album_id")
+
+		var body struct {
+			Tracks []models.AlbumTrack `json:"tracks" binding:"dive"`
+		}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "tracks must be a list of {song_id, disc_number, track_number}"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		var album models.Album
+		if err := albumCollection.FindOne(ctx, bson.M{"album_id": albumID}).Decode(&album); err != nil {
+			if err == mongo.ErrNoDocuments {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Album not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch album"})
+			return
+		}
+
+		if !canManageAlbum(c, album) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to edit this album"})
+			return
+		}
+
+		tracks := body.Tracks
+		if tracks == nil {
+			tracks = []models.AlbumTrack{}
+		}
+		seenSongs := make(map[string]bool)
+		seenPositions := make(map[[2]int]bool)
+		ids := make([]string, 0, len(tracks))
+		for i := range tracks {
+			if tracks[i].DiscNumber <= 0 {
+				tracks[i].DiscNumber = 1
+			}
+			if tracks[i].TrackNumber <= 0 {
+				tracks[i].TrackNumber = i + 1
+			}
+
+			position := [2]int{tracks[i].DiscNumber, tracks[i].TrackNumber}
+			if seenSongs[tracks[i].SongID] || seenPositions[position] {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Each song and each disc/track number may only appear once"})
+				return
+			}
+			seenSongs[tracks[i].SongID] = true
+			seenPositions[position] = true
+			ids = append(ids, tracks[i].SongID)
+		}
+		sortAlbumTracks(tracks)
+
+		cursor, err := songcollection.Find(ctx, bson.M{"song_id": bson.M{"$in": ids}},
+			options.Find().SetProjection(bson.M{"song_id": 1, "uploaded_by": 1, "album_id": 1}),
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch songs"})
+			return
+		}
+		var songs []models.Song
+		if err := cursor.All(ctx, &songs); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse songs"})
+			return
+		}
+
+		if len(songs) != len(ids) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Some songs were not found"})
+			return
+		}
+		for _, song := range songs {
+			if !canManageSong(c, song) {
+				c.JSON(http.StatusForbidden, gin.H{"error": "You can only add your own songs", "song_id": song.SongID})
+				return
+			}
+			if song.AlbumID != nil && *song.AlbumID != albumID {
+				c.JSON(http.StatusConflict, gin.H{"error": "Song is already on another album", "song_id": song.SongID, "album_id": *song.AlbumID})
+				return
+			}
+		}
+
+		now := time.Now()
+		if _, err := albumCollection.UpdateOne(ctx,
+			bson.M{"album_id": albumID},
+			bson.M{"$set": bson.M{"tracks": tracks, "updated_at": now}},
+		); err != nil {
+			log.Println("❌ Failed to save tracks:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save tracks"})
+			return
+		}
+
+		// Link the new track list and unlink songs that were dropped from it
+		if _, err := songcollection.UpdateMany(ctx,
+			bson.M{"album_id": albumID, "song_id": bson.M{"$nin": ids}},
+			bson.M{"$set": bson.M{"album": "", "updated_at": now}, "$unset": bson.M{"album_id": ""}},
+		); err != nil {
+			log.Printf("⚠️ Failed to unlink songs from album %s: %v\n", albumID, err)
+		}
+		if _, err := songcollection.UpdateMany(ctx,
+			bson.M{"song_id": bson.M{"$in": ids}},
+			bson.M{"$set": bson.M{"album_id": albumID, "album": *album.Title, "updated_at": now}},
+		); err != nil {
+			log.Printf("⚠️ Failed to link songs to album %s: %v\n", albumID, err)
+		}
+		reindexSongs(ctx, bson.M{"song_id": bson.M{"$in": append(albumSongIDs(album), ids...)}})
+
+		album.Tracks = tracks
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Tracks updated successfully",
+			"album":   album,
+		})
+	}
+}
+
+// appendAlbumTrack puts a freshly uploaded song at the end of the album's first
+// disc. The track number is worked out by the update itself, so uploads to the
+// same album at once each get their own slot.
+func appendAlbumTrack(ctx context.Context, album models.Album, songID string) error {
+	discOne := bson.M{"$filter": bson.M{
+		"input": bson.M{"$ifNull": bson.A{"$tracks", bson.A{}}},
+		"cond":  bson.M{"$eq": bson.A{"$$this.disc_number", 1}},
+	}}
+	last := bson.M{"$ifNull": bson.A{bson.M{"$max": bson.M{"$map": bson.M{"input": discOne, "in": "$$this.track_number"}}}, 0}}
+	track := bson.M{"song_id": songID, "disc_number": 1, "track_number": bson.M{"$add": bson.A{last, 1}}}
+
+	_, err := albumCollection.UpdateOne(ctx,
+		bson.M{"album_id": album.AlbumID, "tracks.song_id": bson.M{"$ne": songID}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{
+			// Disc 1 tracks come before any later disc's
+			"tracks": bson.M{"$concatArrays": bson.A{
+				discOne,
+				bson.A{track},
+				bson.M{"$filter": bson.M{
+					"input": bson.M{"$ifNull": bson.A{"$tracks", bson.A{}}},
+					"cond":  bson.M{"$ne": bson.A{"$$this.disc_number", 1}},
+				}},
+			}},
+			"updated_at": time.Now(),
+		}}}},
+	)
+	return err
+}
+
+func albumSongIDs(album models.Album) []string {
+	ids := make([]string, 0, len(album.Tracks))
+	for _, track := range album.Tracks {
+		ids = append(ids, track.SongID)
+	}
+	return ids
+}
+
+// formList reads a comma-separated form field
+func formList(c *gin.Context, key string) []string {
+	values := []string{}
+	for _, value := range strings.Split(c.PostForm(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,255 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ishanbagra18/ecommerce-using-go/helpers"
+	"github.com/ishanbagra18/ecommerce-using-go/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Songs bigger than this are not downloaded for analysis
+const maxAnalysisFileSize = 100 << 20
+
+// How often the worker looks for songs that were never analysed
+// (older uploads, or uploads queued while the worker was busy)
+const analysisPollInterval = 5 * time.Minute
+
+const (
+	// A song still processing after this was left behind by a crash or restart
+	analysisStaleAfter = 15 * time.Minute
+	// Failed songs are retried by the scan until they have failed this often
+	analysisMaxAttempts = 3
+)
+
+// analysisClaimable matches songs the worker may start on: never analysed,
+// abandoned mid-analysis, or failed with retries left
+func analysisClaimable(now time.Time) bson.M {
+	return bson.M{"$or": bson.A{
+		bson.M{"analysis_status": bson.M{"$in": []interface{}{nil, models.AnalysisPending}}},
+		bson.M{"analysis_status": models.AnalysisProcessing, "processing_started_at": bson.M{"$not": bson.M{"$gt": now.Add(-analysisStaleAfter)}}},
+		bson.M{"analysis_status": models.AnalysisFailed, "analysis_attempts": bson.M{"$lt": analysisMaxAttempts}},
+	}}
+}
+
+var analysisQueue = make(chan string, 100)
+
+var analysisHTTPClient = &http.Client{Timeout: 2 * time.Minute}
+
+// QueueSongAnalysis asks the background worker to analyse a song.
+// It never blocks; if the queue is full the periodic scan picks the song up.
+func QueueSongAnalysis(songID string) {
+	select {
+	case analysisQueue <- songID:
+	default:
+		log.Printf("⚠️ Analysis queue full, song %s will be picked up by the next scan\n", songID)
+	}
+}
+
+// StartAnalysisWorker runs the waveform/loudness analysis job in the background
+func StartAnalysisWorker() {
+	go func() {
+		log.Println("✔ Audio analysis worker started")
+
+		ticker := time.NewTicker(analysisPollInterval)
+		defer ticker.Stop()
+
+		scanPendingAnalysis()
+
+		for {
+			select {
+			case songID := <-analysisQueue:
+				analyzeSong(songID)
+			case <-ticker.C:
+				scanPendingAnalysis()
+			}
+		}
+	}()
+}
+
+// scanPendingAnalysis analyses songs that have no analysis yet, were
+// abandoned mid-analysis, or failed and have retries left
+func scanPendingAnalysis() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := analysisClaimable(time.Now())
+	opts := options.Find().SetProjection(bson.M{"song_id": 1}).SetLimit(20)
+
+	cursor, err := songcollection.Find(ctx, filter, opts)
+	if err != nil {
+		log.Println("❌ Failed to fetch songs pending analysis:", err)
+		return
+	}
+
+	var songs []models.Song
+	if err := cursor.All(ctx, &songs); err != nil {
+		log.Println("❌ Failed to parse songs pending analysis:", err)
+		return
+	}
+
+	for _, song := range songs {
+		analyzeSong(song.SongID)
+	}
+}
+
+// analyzeSong downloads a song, decodes it and stores its peaks and loudness
+func analyzeSong(songID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	// A song someone queued explicitly is analysed again whatever its state,
+	// unless another attempt is still running
+	now := time.Now()
+	var song models.Song
+	err := songcollection.FindOneAndUpdate(ctx,
+		bson.M{"song_id": songID, "$or": bson.A{
+			bson.M{"analysis_status": bson.M{"$ne": models.AnalysisProcessing}},
+			bson.M{"processing_started_at": bson.M{"$not": bson.M{"$gt": now.Add(-analysisStaleAfter)}}},
+		}},
+		bson.M{"$set": bson.M{"analysis_status": models.AnalysisProcessing, "processing_started_at": now}},
+	).Decode(&song)
+	if err != nil {
+		if err != mongo.ErrNoDocuments {
+			log.Printf("❌ Failed to claim song %s for analysis: %v\n", songID, err)
+		}
+		return
+	}
+
+	log.Printf("🎧 Analysing song %s\n", songID)
+
+	analysis, err := downloadAndAnalyze(ctx, song)
+	if err != nil {
+		log.Printf("❌ Analysis failed for song %s: %v\n", songID, err)
+		msg := err.Error()
+		// A fresh context: the failure may be the analysis running out of time
+		saveCtx, saveCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer saveCancel()
+		_, _ = songcollection.UpdateOne(saveCtx, bson.M{"song_id": songID}, bson.M{
+			"$set":   bson.M{"analysis_status": models.AnalysisFailed, "analysis_error": msg},
+			"$inc":   bson.M{"analysis_attempts": 1},
+			"$unset": bson.M{"processing_started_at": ""},
+		})
+		return
+	}
+
+	now = time.Now()
+	update := bson.M{
+		"waveform":        analysis.Peaks,
+		"loudness_lufs":   analysis.LoudnessLUFS,
+		"analysis_status": models.AnalysisDone,
+		"analyzed_at":     now,
+	}
+	// Fill in the duration if the uploader didn't give one
+	if song.Duration == 0 {
+		update["duration"] = int(math.Round(analysis.Duration))
+	}
+
+	_, err = songcollection.UpdateOne(ctx, bson.M{"song_id": songID}, bson.M{
+		"$set":   update,
+		"$unset": bson.M{"analysis_error": "", "analysis_attempts": "", "processing_started_at": ""},
+	})
+	if err != nil {
+		log.Printf("❌ Failed to save analysis for song %s: %v\n", songID, err)
+		return
+	}
+
+	log.Printf("✅ Song %s analysed: %.1f LUFS, %.0fs\n", songID, analysis.LoudnessLUFS, analysis.Duration)
+}
+
+func downloadAndAnalyze(ctx context.Context, song models.Song) (*helpers.AudioAnalysis, error) {
+	if song.FileURL == nil || *song.FileURL == "" {
+		return nil, fmt.Errorf("song has no file_url")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, *song.FileURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := analysisHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download failed with status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxAnalysisFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxAnalysisFileSize {
+		return nil, fmt.Errorf("file is larger than %d bytes", maxAnalysisFileSize)
+	}
+
+	return helpers.AnalyzeAudio(data)
+}
+
+// GetSongWaveform returns the waveform peaks and loudness of a song
+func GetSongWaveform() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		songID := c.Param("song_id")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var song models.Song
+		opts := options.FindOne().SetProjection(bson.M{
+			"song_id": 1, "waveform": 1, "loudness_lufs": 1, "duration": 1, "analysis_status": 1,
+			"status": 1, "uploaded_by": 1, "is_released": 1, "release_date": 1, "explicit": 1, "availability": 1,
+		})
+		err := songcollection.FindOne(ctx, bson.M{"song_id": songID}, opts).Decode(&song)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				c.JSON(http.StatusNotFound, gin.H{"error": "song not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch song"})
+			return
+		}
+
+		if !viewerFromContext(c).canView(song) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "song not found"})
+			return
+		}
+
+		if song.AnalysisStatus != models.AnalysisDone {
+			status := song.AnalysisStatus
+			if status == "" {
+				status = models.AnalysisPending
+			}
+			c.JSON(http.StatusAccepted, gin.H{
+				"song_id": song.SongID,
+				"status":  status,
+			})
+			return
+		}
+
+		response := gin.H{
+			"song_id":       song.SongID,
+			"status":        song.AnalysisStatus,
+			"peaks":         song.Waveform,
+			"duration":      song.Duration,
+			"loudness_lufs": song.LoudnessLUFS,
+		}
+		// Gain the player should apply to reach the target loudness
+		if song.LoudnessLUFS != nil {
+			response["gain_db"] = math.Round((helpers.TargetLoudnessLUFS-*song.LoudnessLUFS)*10) / 10
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -23,6 +24,26 @@ var artistCollection *mongo.Collection
 func InitArtistController() {
 	artistCollection = database.GetCollection("ecommerce", "artists")
 	log.Println("✅ [InitArtistController] Artist collection initialized")
+
+	database.CreateIndexes(artistCollection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "artist_id", Value: 1}}},
+		{
+			Keys: bson.D{{Key: "name", Value: "text"}, {Key: "genre", Value: "text"}, {Key: "bio", Value: "text"}},
+			Options: options.Index().
+				SetName("artist_text").
+				SetDefaultLanguage("none").
+				SetWeights(bson.D{{Key: "name", Value: 10}, {Key: "genre", Value: 3}, {Key: "bio", Value: 1}}),
+		},
+	})
+	// Backs the case-insensitive name lookups made when crediting songs, and
+	// stops two uploads creating the same artist at once. Created on its own:
+	// on older databases it waits for the songs_link_artist_ids migration.
+	database.CreateIndexes(artistCollection, []mongo.IndexModel{artistNameIndex})
+}
+
+var artistNameIndex = mongo.IndexModel{
+	Keys:    bson.D{{Key: "name", Value: 1}},
+	Options: options.Index().SetCollation(artistNameCollation).SetUnique(true),
 }
 
 // GetAllArtists retrieves all artists with pagination					//done
@@ -89,6 +110,13 @@ func CreateArtist() gin.HandlerFunc {
 			return
 		}
 
+		genres, err := normalizeGenreNames(ctx, artist.Genre)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve genres"})
+			return
+		}
+		artist.Genre = genres
+
 		artistCollection := database.GetCollection("ecommerce", "artists")
 
 		artist.ID = primitive.NewObjectID()
@@ -100,7 +128,7 @@ func CreateArtist() gin.HandlerFunc {
 		artist.Created_at = &now
 		artist.Updated_at = &now
 
-		_, err := artistCollection.InsertOne(ctx, artist)
+		_, err = artistCollection.InsertOne(ctx, artist)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
@@ -151,7 +179,12 @@ func UpdateArtist() gin.HandlerFunc {
 		}
 
 		if len(updateArtist.Genre) > 0 {
-			updateData["genre"] = updateArtist.Genre
+			genres, err := normalizeGenreNames(ctx, updateArtist.Genre)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve genres"})
+				return
+			}
+			updateData["genre"] = genres
 		}
 
 		if updateArtist.ImageURL != nil {
@@ -182,6 +215,18 @@ func UpdateArtist() gin.HandlerFunc {
 			return
 		}
 
+		// Credits carry a copy of the name
+		if updateArtist.Name != nil {
+			_, err := songcollection.UpdateMany(ctx,
+				bson.M{"artist_ids": artistID},
+				bson.M{"$set": bson.M{"artist_credits.$[credit].name": *updateArtist.Name}},
+				options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{bson.M{"credit.artist_id": artistID}}}),
+			)
+			if err != nil {
+				log.Printf("⚠️ Failed to rename credits of artist %s: %v\n", artistID, err)
+			}
+		}
+
 		// Fetch and return the updated artist
 		var updatedArtist models.Artist
 		err = artistCollection.FindOne(ctx, bson.M{"artist_id": artistID}).Decode(&updatedArtist)
@@ -215,6 +260,21 @@ func DeleteArtist() gin.HandlerFunc {
 			return
 		}
 
+		// Songs keep their artist line but lose the link
+		_, err = songcollection.UpdateMany(ctx,
+			bson.M{"artist_ids": artistID},
+			bson.M{"$pull": bson.M{
+				"artist_ids":     artistID,
+				"artist_credits": bson.M{"artist_id": artistID},
+			}},
+		)
+		if err != nil {
+			log.Printf("⚠️ Failed to unlink songs of artist %s: %v\n", artistID, err)
+		}
+		if _, err := relatedArtistCollection.DeleteOne(ctx, bson.M{"artist_id": artistID}); err != nil {
+			log.Printf("⚠️ Failed to drop related artists of %s: %v\n", artistID, err)
+		}
+
 		c.JSON(http.StatusOK, gin.H{"message": "Artist deleted successfully"})
 	}
 }
@@ -461,7 +521,7 @@ func CheckIfFollowing() gin.HandlerFunc {
 	}
 }
 
-// GetArtistSongs retrieves all songs by a specific artist
+// GetArtistSongs retrieves all songs credited to an artist, optionally only in one role (?role=featured)
 func GetArtistSongs() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		artistID := c.Param("artist_id")
@@ -492,27 +552,22 @@ func GetArtistSongs() gin.HandlerFunc {
 		}
 
 		artistName := *artist.Name
-		log.Printf("🔍 Searching for songs by artist: %s", artistName)
-
-		// 🔹 Fetch songs where artist exists in artists array or artist string
-		songCollection := database.GetCollection("ecommerce", "songs")
-
-		// Simpler approach: search for artist name as substring in both fields
-		// This handles: arrays, comma-separated strings, and single values
-		artistPattern := primitive.Regex{Pattern: artistName, Options: "i"}
 
-		filter := bson.M{
-			"$or": []bson.M{
-				{"artists": artistPattern}, // Search in artists array or string
-				{"artist": artistPattern},  // Search in artist field
-			},
+		// Songs are linked by ID, so "Ali" no longer matches "Alizeh"
+		filter := bson.M{"artist_ids": artistID}
+		if role := models.ArtistRole(strings.ToLower(c.Query("role"))); role != "" {
+			if !models.IsValidArtistRole(role) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "role must be primary, featured, producer or composer"})
+				return
+			}
+			filter = bson.M{"artist_credits": bson.M{"$elemMatch": bson.M{"artist_id": artistID, "role": role}}}
 		}
 
-		log.Printf("🔍 Query filter: %+v", filter)
+		songCollection := database.GetCollection("ecommerce", "songs")
 
 		opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
 
-		cursor, err := songCollection.Find(ctx, filter, opts)
+		cursor, err := songCollection.Find(ctx, publicSongFilter(c, filter), opts)
 		if err != nil {
 			log.Println("❌ Error fetching songs:", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch songs"})
@@ -0,0 +1,200 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ishanbagra18/ecommerce-using-go/helpers"
+	"github.com/ishanbagra18/ecommerce-using-go/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Artist names are matched ignoring case ("arijit singh" is "Arijit Singh")
+var artistNameCollation = &options.Collation{Locale: "en", Strength: 2}
+
+var errInvalidCredit = errors.New("invalid artist credit")
+
+// creditInput is one credit as sent by a client: an existing artist by ID,
+// or a name that is looked up and created when no artist has it yet
+type creditInput struct {
+	ArtistID string            `json:"artist_id"`
+	Name     string            `json:"name"`
+	Role     models.ArtistRole `json:"role"`
+}
+
+// creditsFromForm reads the optional "credits" form field, a JSON list like
+// [{"name": "Arijit Singh", "role": "primary"}, {"artist_id": "...", "role": "featured"}]
+func creditsFromForm(c *gin.Context) ([]creditInput, bool, error) {
+	raw, ok := c.GetPostForm("credits")
+	if !ok || strings.TrimSpace(raw) == "" {
+		return nil, false, nil
+	}
+
+	var inputs []creditInput
+	if err := json.Unmarshal([]byte(raw), &inputs); err != nil {
+		return nil, true, fmt.Errorf("%w: credits must be a JSON list", errInvalidCredit)
+	}
+	return inputs, true, nil
+}
+
+// creditsFromArtistString credits the names in a free-text artist field:
+// those after "feat." as featured, the rest as primary
+func creditsFromArtistString(ctx context.Context, artist string) ([]creditInput, error) {
+	main, featured := helpers.SplitFeaturedArtists(artist)
+
+	var inputs []creditInput
+	for _, part := range []struct {
+		line string
+		role models.ArtistRole
+	}{{main, models.ArtistRolePrimary}, {featured, models.ArtistRoleFeatured}} {
+		names, err := artistNamesFromLine(ctx, part.line)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			inputs = append(inputs, creditInput{Name: name, Role: part.role})
+		}
+	}
+	return inputs, nil
+}
+
+// artistNamesFromLine splits a line of artist names. The line, or any
+// comma-separated part of it, that names an existing artist is kept whole so
+// "Simon & Garfunkel" or "Vishal and Shekhar" aren't taken apart.
+func artistNamesFromLine(ctx context.Context, line string) ([]string, error) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return nil, nil
+	}
+	if found, err := artistNameExists(ctx, line); err != nil || found {
+		return []string{line}, err
+	}
+
+	var names []string
+	for _, part := range strings.Split(line, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		found, err := artistNameExists(ctx, part)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			names = append(names, part)
+			continue
+		}
+		names = append(names, helpers.SplitArtistNames(part)...)
+	}
+	return names, nil
+}
+
+func artistNameExists(ctx context.Context, name string) (bool, error) {
+	count, err := artistCollection.CountDocuments(ctx, bson.M{"name": name}, options.Count().SetCollation(artistNameCollation).SetLimit(1))
+	return count > 0, err
+}
+
+// findOrCreateArtist returns the artist with this name, creating a bare
+// profile for names the catalogue hasn't seen yet
+func findOrCreateArtist(ctx context.Context, name string) (models.Artist, error) {
+	var artist models.Artist
+	err := artistCollection.FindOne(ctx, bson.M{"name": name}, options.FindOne().SetCollation(artistNameCollation)).Decode(&artist)
+	if err != mongo.ErrNoDocuments {
+		return artist, err
+	}
+
+	now := time.Now()
+	artist = models.Artist{
+		ID:         primitive.NewObjectID(),
+		Name:       &name,
+		Genre:      []string{},
+		Followers:  []string{},
+		Created_at: &now,
+		Updated_at: &now,
+	}
+	artist.Artist_id = artist.ID.Hex()
+
+	_, err = artistCollection.InsertOne(ctx, artist)
+	// Someone else created it first; the unique name index caught it
+	if mongo.IsDuplicateKeyError(err) {
+		err = artistCollection.FindOne(ctx, bson.M{"name": name}, options.FindOne().SetCollation(artistNameCollation)).Decode(&artist)
+	}
+	return artist, err
+}
+
+// resolveArtistCredits turns client credits into stored credits plus the list
+// of distinct artist IDs. Roles default to primary.
+func resolveArtistCredits(ctx context.Context, inputs []creditInput) ([]models.ArtistCredit, []string, error) {
+	credits := []models.ArtistCredit{}
+	ids := []string{}
+	seen := make(map[string]bool)
+
+	for _, input := range inputs {
+		role := models.ArtistRole(strings.ToLower(strings.TrimSpace(string(input.Role))))
+		if role == "" {
+			role = models.ArtistRolePrimary
+		}
+		if !models.IsValidArtistRole(role) {
+			return nil, nil, fmt.Errorf("%w: role must be primary, featured, producer or composer", errInvalidCredit)
+		}
+
+		var artist models.Artist
+		var err error
+		switch {
+		case input.ArtistID != "":
+			err = artistCollection.FindOne(ctx, bson.M{"artist_id": input.ArtistID}).Decode(&artist)
+			if err == mongo.ErrNoDocuments {
+				return nil, nil, fmt.Errorf("%w: artist %s not found", errInvalidCredit, input.ArtistID)
+			}
+		case strings.TrimSpace(input.Name) != "":
+			artist, err = findOrCreateArtist(ctx, strings.TrimSpace(input.Name))
+		default:
+			return nil, nil, fmt.Errorf("%w: each credit needs an artist_id or a name", errInvalidCredit)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		key := artist.Artist_id + "|" + string(role)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		credits = append(credits, models.ArtistCredit{ArtistID: artist.Artist_id, Name: *artist.Name, Role: role})
+		if !containsString(ids, artist.Artist_id) {
+			ids = append(ids, artist.Artist_id)
+		}
+	}
+
+	return credits, ids, nil
+}
+
+// creditsDisplayName builds the artist line for songs uploaded with credits
+// only: "A, B feat. C"
+func creditsDisplayName(credits []models.ArtistCredit) string {
+	var primary, featured []string
+	for _, credit := range credits {
+		switch credit.Role {
+		case models.ArtistRolePrimary:
+			primary = append(primary, credit.Name)
+		case models.ArtistRoleFeatured:
+			featured = append(featured, credit.Name)
+		}
+	}
+
+	name := strings.Join(primary, ", ")
+	if len(featured) > 0 {
+		name += " feat. " + strings.Join(featured, ", ")
+	}
+	return strings.TrimSpace(name)
+}
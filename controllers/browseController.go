@@ -0,0 +1,370 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ishanbagra18/ecommerce-using-go/helpers"
+	"github.com/ishanbagra18/ecommerce-using-go/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// songSort is one ordering of the catalogue. value reads the sort key back
+// from the last song of a page to build the next cursor.
+type songSort struct {
+	field string
+	order int
+	value func(song models.Song) interface{}
+}
+
+var songSorts = map[string]songSort{
+	"newest": {field: "released_at", order: -1, value: func(song models.Song) interface{} {
+		if song.ReleaseDate != nil {
+			return *song.ReleaseDate
+		}
+		if song.CreatedAt != nil {
+			return *song.CreatedAt
+		}
+		return nil
+	}},
+	"most_played": {field: "play_count", order: -1, value: func(song models.Song) interface{} {
+		return song.PlayCount
+	}},
+	"most_liked": {field: "like_count", order: -1, value: func(song models.Song) interface{} {
+		return song.LikeCount
+	}},
+	"title": {field: "title", order: 1, value: func(song models.Song) interface{} {
+		if song.Title != nil {
+			return *song.Title
+		}
+		return nil
+	}},
+}
+
+var errInvalidBrowseQuery = errors.New("invalid query")
+
+type facetCount struct {
+	Value interface{} `bson:"_id" json:"value"`
+	Count int64       `bson:"count" json:"count"`
+}
+
+// BrowseSongs lists the catalogue with filters, a choice of ordering, facet
+// counts for the current filters and cursor pagination.
+//
+//	GET /music/songs?genre=pop,rock&mood=chill&language=hindi&artist=Arijit Singh&artist_id=...&album=...&album_id=...
+//	    &year_from=2015&year_to=2020&min_duration=120&max_duration=300
+//	    &sort=newest|most_played|most_liked|title&limit=20&cursor=...
+func BrowseSongs() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log.Println("🔹 BrowseSongs endpoint hit")
+		browseSongs(c, nil)
+	}
+}
+
+// browseSongs serves a browse page. scope narrows the catalogue before the
+// query string filters apply, for endpoints like /genres/:slug/songs.
+func browseSongs(c *gin.Context, scope bson.M) {
+	filter, err := browseFilter(c)
+	if err != nil {
+		if errors.Is(err, errInvalidBrowseQuery) || errors.Is(err, errUnknownGenre) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Println("❌ Failed to build browse filter:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch songs"})
+		return
+	}
+	if len(scope) > 0 {
+		filter = bson.M{"$and": bson.A{scope, filter}}
+	}
+
+	sortName := c.DefaultQuery("sort", "newest")
+	sort, ok := songSorts[sortName]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sort, use one of: newest | most_played | most_liked | title"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	// Songs after the cursor, in the same order as the sort
+	pageFilter := bson.M{}
+	if cursor := c.Query("cursor"); cursor != "" {
+		value, lastID, err := helpers.DecodeCursor(cursor, sortName)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cursor"})
+			return
+		}
+
+		pageFilter = afterCursor(sort, value, lastID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: publicSongFilter(c, filter)}},
+		{{Key: "$addFields", Value: bson.M{
+			"released_at": bson.M{"$ifNull": bson.A{"$release_date", "$created_at"}},
+		}}},
+		{{Key: "$facet", Value: bson.M{
+			"items": bson.A{
+				bson.M{"$match": pageFilter},
+				bson.M{"$sort": bson.D{{Key: sort.field, Value: sort.order}, {Key: "_id", Value: 1}}},
+				bson.M{"$limit": limit + 1},
+				bson.M{"$project": bson.M{"waveform": 0, "user_play_counts": 0, "released_at": 0}},
+			},
+			"genre":    facetStages("$genre"),
+			"language": facetStages("$language"),
+			"year":     facetStages(bson.M{"$year": "$released_at"}),
+			"total":    bson.A{bson.M{"$count": "count"}},
+		}}},
+	}
+
+	cursor, err := songcollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		log.Println("❌ Failed to browse songs:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch songs"})
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var result []struct {
+		Items    []models.Song `bson:"items"`
+		Genre    []facetCount  `bson:"genre"`
+		Language []facetCount  `bson:"language"`
+		Year     []facetCount  `bson:"year"`
+		Total    []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+	}
+	if err := cursor.All(ctx, &result); err != nil || len(result) == 0 {
+		log.Println("❌ Failed to parse browsed songs:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse songs"})
+		return
+	}
+	page := result[0]
+
+	songs := page.Items
+	if songs == nil {
+		songs = []models.Song{}
+	}
+
+	// One extra song was fetched to know whether another page exists
+	var nextCursor *string
+	if len(songs) > limit {
+		songs = songs[:limit]
+		last := songs[limit-1]
+		encoded, err := helpers.EncodeCursor(sortName, sort.value(last), last.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build cursor"})
+			return
+		}
+		nextCursor = &encoded
+	}
+
+	var total int64
+	if len(page.Total) > 0 {
+		total = page.Total[0].Count
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"songs":       songs,
+		"next_cursor": nextCursor,
+		"total":       total,
+		"facets": gin.H{
+			"genre":    nonNilFacets(page.Genre),
+			"language": nonNilFacets(page.Language),
+			"year":     nonNilFacets(page.Year),
+		},
+	})
+}
+
+// afterCursor matches the songs that sort after the cursor position. Songs
+// missing the sort field sort as null, before everything else ascending and
+// after everything else descending, and comparisons like $gt never match
+// null, so those songs are handled on their own.
+func afterCursor(sort songSort, value interface{}, lastID primitive.ObjectID) bson.M {
+	sameValue := bson.M{sort.field: value, "_id": bson.M{"$gt": lastID}}
+	if value == nil {
+		if sort.order < 0 {
+			return sameValue
+		}
+		return bson.M{"$or": bson.A{sameValue, bson.M{sort.field: bson.M{"$ne": nil}}}}
+	}
+
+	if sort.order < 0 {
+		return bson.M{"$or": bson.A{
+			bson.M{sort.field: bson.M{"$lt": value}},
+			sameValue,
+			bson.M{sort.field: nil},
+		}}
+	}
+	return bson.M{"$or": bson.A{
+		bson.M{sort.field: bson.M{"$gt": value}},
+		sameValue,
+	}}
+}
+
+// browseFilter turns the query string into a songs filter
+func browseFilter(c *gin.Context) (bson.M, error) {
+	conditions := bson.A{}
+
+	if genres := queryList(c, "genre"); len(genres) > 0 {
+		slugs, err := genreSlugsWithDescendants(c.Request.Context(), genres)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, bson.M{"genre_slug": bson.M{"$in": slugs}})
+	}
+	if moods := queryList(c, "mood"); len(moods) > 0 {
+		slugs, err := resolveMoods(c.Request.Context(), moods)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, bson.M{"moods": bson.M{"$in": slugs}})
+	}
+	if languages := queryList(c, "language"); len(languages) > 0 {
+		conditions = append(conditions, bson.M{"language": bson.M{"$in": exactPatterns(languages)}})
+	}
+	if albumID := strings.TrimSpace(c.Query("album_id")); albumID != "" {
+		conditions = append(conditions, bson.M{"album_id": albumID})
+	}
+	if album := strings.TrimSpace(c.Query("album")); album != "" {
+		conditions = append(conditions, bson.M{"album": bson.M{"$in": exactPatterns([]string{album})}})
+	}
+	if artistIDs := queryList(c, "artist_id"); len(artistIDs) > 0 {
+		conditions = append(conditions, bson.M{"artist_ids": bson.M{"$in": artistIDs}})
+	}
+	if artist := strings.TrimSpace(c.Query("artist")); artist != "" {
+		// Whole names only, so "ali" doesn't bring up "Alisha". Songs linked
+		// to a profile with that name match even when they credit others too.
+		matches := bson.A{
+			bson.M{"artists": bson.M{"$in": exactPatterns([]string{artist})}},
+			bson.M{"artist": bson.M{"$in": exactPatterns([]string{artist})}},
+		}
+		artistIDs, err := artistCollection.Distinct(c.Request.Context(), "artist_id", bson.M{"name": artist},
+			options.Distinct().SetCollation(artistNameCollation),
+		)
+		if err != nil {
+			return nil, err
+		}
+		if len(artistIDs) > 0 {
+			matches = append(matches, bson.M{"artist_ids": bson.M{"$in": artistIDs}})
+		}
+		conditions = append(conditions, bson.M{"$or": matches})
+	}
+
+	yearFrom, err := queryInt(c, "year_from")
+	if err != nil {
+		return nil, err
+	}
+	yearTo, err := queryInt(c, "year_to")
+	if err != nil {
+		return nil, err
+	}
+	if yearFrom != nil || yearTo != nil {
+		// Songs without a release date count from when they were uploaded
+		releasedAt := bson.M{}
+		if yearFrom != nil {
+			releasedAt["$gte"] = time.Date(*yearFrom, time.January, 1, 0, 0, 0, 0, time.UTC)
+		}
+		if yearTo != nil {
+			releasedAt["$lt"] = time.Date(*yearTo+1, time.January, 1, 0, 0, 0, 0, time.UTC)
+		}
+		conditions = append(conditions, bson.M{"$or": bson.A{
+			bson.M{"release_date": releasedAt},
+			bson.M{"release_date": nil, "created_at": releasedAt},
+		}})
+	}
+
+	minDuration, err := queryInt(c, "min_duration")
+	if err != nil {
+		return nil, err
+	}
+	maxDuration, err := queryInt(c, "max_duration")
+	if err != nil {
+		return nil, err
+	}
+	if minDuration != nil || maxDuration != nil {
+		duration := bson.M{}
+		if minDuration != nil {
+			duration["$gte"] = *minDuration
+		}
+		if maxDuration != nil {
+			duration["$lte"] = *maxDuration
+		}
+		conditions = append(conditions, bson.M{"duration": duration})
+	}
+
+	if len(conditions) == 0 {
+		return bson.M{}, nil
+	}
+	return bson.M{"$and": conditions}, nil
+}
+
+// queryList reads a comma-separated query parameter
+func queryList(c *gin.Context, key string) []string {
+	var values []string
+	for _, value := range strings.Split(c.Query(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func queryInt(c *gin.Context, key string) (*int, error) {
+	raw := strings.TrimSpace(c.Query(key))
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 0 {
+		return nil, fmt.Errorf("%w: %s must be a non-negative number", errInvalidBrowseQuery, key)
+	}
+	return &value, nil
+}
+
+// exactPatterns matches any of the values, whole and case-insensitively
+func exactPatterns(values []string) bson.A {
+	patterns := make(bson.A, 0, len(values))
+	for _, value := range values {
+		patterns = append(patterns, primitive.Regex{Pattern: "^" + regexp.QuoteMeta(value) + "$", Options: "i"})
+	}
+	return patterns
+}
+
+// facetStages counts songs per distinct value of expr, most common first
+func facetStages(expr interface{}) bson.A {
+	return bson.A{
+		bson.M{"$group": bson.M{"_id": expr, "count": bson.M{"$sum": 1}}},
+		bson.M{"$match": bson.M{"_id": bson.M{"$nin": bson.A{nil, ""}}}},
+		bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+	}
+}
+
+func nonNilFacets(facets []facetCount) []facetCount {
+	if facets == nil {
+		return []facetCount{}
+	}
+	return facets
+}
@@ -0,0 +1,696 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ishanbagra18/ecommerce-using-go/database"
+	"github.com/ishanbagra18/ecommerce-using-go/helpers"
+	"github.com/ishanbagra18/ecommerce-using-go/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	commentMaxLength       = 2000
+	commentMaxLinks        = 2
+	commentReportThreshold = 3 // reports that hide a comment until an admin looks
+)
+
+var commentCollection *mongo.Collection
+
+var errCommentChanged = errors.New("comment changed, try again")
+
+func InitCommentController() {
+	commentCollection = database.OpenCollection(database.Client, "comments")
+	log.Println("✔ Comment collection initialized")
+
+	database.CreateIndexes(commentCollection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "comment_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "song_id", Value: 1}, {Key: "parent_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "song_id", Value: 1}, {Key: "timestamp", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}}},
+	})
+}
+
+// commentCheck is a moderation hook run on every new or edited comment. It
+// returns a reason when the comment should be held for an admin to review.
+type commentCheck func(ctx context.Context, comment models.Comment) (string, error)
+
+// commentChecks run in order; the first reason given holds the comment
+var commentChecks = []commentCheck{checkCommentLinks, checkCommentBlockedWords}
+
+func checkCommentLinks(ctx context.Context, comment models.Comment) (string, error) {
+	body := strings.ToLower(comment.Body)
+	links := strings.Count(body, "http://") + strings.Count(body, "https://") + strings.Count(body, "www.")
+	if links > commentMaxLinks {
+		return "too many links", nil
+	}
+	return "", nil
+}
+
+// checkCommentBlockedWords holds comments using any of the comma-separated
+// words in COMMENT_BLOCKED_WORDS
+func checkCommentBlockedWords(ctx context.Context, comment models.Comment) (string, error) {
+	body := strings.ToLower(comment.Body)
+	for _, word := range strings.Split(os.Getenv("COMMENT_BLOCKED_WORDS"), ",") {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word != "" && strings.Contains(body, word) {
+			return "blocked word", nil
+		}
+	}
+	return "", nil
+}
+
+// moderateComment runs the checks and sets the comment visible or pending
+func moderateComment(ctx context.Context, comment *models.Comment) error {
+	comment.Status = models.CommentVisible
+	comment.ModerationReason = nil
+	for _, check := range commentChecks {
+		reason, err := check(ctx, *comment)
+		if err != nil {
+			return err
+		}
+		if reason != "" {
+			comment.Status = models.CommentPending
+			comment.ModerationReason = &reason
+			return nil
+		}
+	}
+	return nil
+}
+
+// saveComment writes a changed comment, refusing if someone else changed it
+// since it was read, and keeps the song's comment_count and the parent's
+// reply_count in step. A zero before means the comment is new.
+func saveComment(ctx context.Context, before models.Comment, after models.Comment) error {
+	if before.ID.IsZero() {
+		if _, err := commentCollection.InsertOne(ctx, after); err != nil {
+			return err
+		}
+	} else {
+		result, err := commentCollection.ReplaceOne(ctx,
+			bson.M{"comment_id": before.CommentID, "updated_at": before.UpdatedAt},
+			after,
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return errCommentChanged
+		}
+	}
+	return adjustCommentCounts(ctx, after, countOf(after)-countOf(before))
+}
+
+func countOf(comment models.Comment) int {
+	if comment.Counted() {
+		return 1
+	}
+	return 0
+}
+
+func adjustCommentCounts(ctx context.Context, comment models.Comment, delta int) error {
+	if delta == 0 {
+		return nil
+	}
+	if _, err := songcollection.UpdateOne(ctx,
+		bson.M{"song_id": comment.SongID},
+		bson.M{"$inc": bson.M{"comment_count": delta}},
+	); err != nil {
+		return err
+	}
+	if comment.ParentID != nil {
+		if _, err := commentCollection.UpdateOne(ctx,
+			bson.M{"comment_id": *comment.ParentID},
+			bson.M{"$inc": bson.M{"reply_count": delta}},
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// respondCommentSave answers a failed saveComment
+func respondCommentSave(c *gin.Context, err error) {
+	if err == errCommentChanged {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	log.Println("❌ Failed to save comment:", err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save comment"})
+}
+
+// findComment loads a comment by the :comment_id path parameter
+func findComment(ctx context.Context, c *gin.Context) (models.Comment, bool) {
+	var comment models.Comment
+	err := commentCollection.FindOne(ctx, bson.M{"comment_id": c.Param("comment_id")}).Decode(&comment)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+			return comment, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comment"})
+		return comment, false
+	}
+	return comment, true
+}
+
+// visibleSong loads a song the current request may see
+func visibleSong(ctx context.Context, c *gin.Context, songID string) (models.Song, bool) {
+	var song models.Song
+	err := songcollection.FindOne(ctx, publicSongFilter(c, bson.M{"song_id": songID}),
+		options.FindOne().SetProjection(bson.M{"song_id": 1, "duration": 1, "comment_count": 1}),
+	).Decode(&song)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, gin.H{"error": "song not found"})
+			return song, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch song"})
+		return song, false
+	}
+	return song, true
+}
+
+type commentSort struct {
+	field string
+	order int
+	value func(models.Comment) interface{}
+}
+
+var commentSorts = map[string]commentSort{
+	"newest":    {"created_at", -1, func(comment models.Comment) interface{} { return comment.CreatedAt }},
+	"oldest":    {"created_at", 1, func(comment models.Comment) interface{} { return comment.CreatedAt }},
+	"timestamp": {"timestamp", 1, func(comment models.Comment) interface{} { return *comment.Timestamp }},
+}
+
+// commentPage serves one page of comments matching filter, with a cursor for
+// the next. Others see visible comments; authors also see their own held ones.
+func commentPage(c *gin.Context, ctx context.Context, filter bson.M, sortName string, extra gin.H) {
+	sort, ok := commentSorts[sortName]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sort, use one of: newest | oldest | timestamp"})
+		return
+	}
+	if sort.field == "timestamp" {
+		filter["timestamp"] = bson.M{"$exists": true}
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	seen := bson.A{bson.M{"status": models.CommentVisible}}
+	if userID := c.GetString("user_id"); userID != "" {
+		seen = append(seen, bson.M{"status": models.CommentPending, "user_id": userID})
+	}
+	conditions := bson.A{filter, bson.M{"$or": seen}}
+
+	if cursor := c.Query("cursor"); cursor != "" {
+		value, lastID, err := helpers.DecodeCursor(cursor, sortName)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cursor"})
+			return
+		}
+		cmp := "$gt"
+		if sort.order < 0 {
+			cmp = "$lt"
+		}
+		conditions = append(conditions, bson.M{"$or": bson.A{
+			bson.M{sort.field: bson.M{cmp: value}},
+			bson.M{sort.field: value, "_id": bson.M{"$gt": lastID}},
+		}})
+	}
+
+	cursor, err := commentCollection.Find(ctx,
+		bson.M{"$and": conditions},
+		options.Find().
+			SetSort(bson.D{{Key: sort.field, Value: sort.order}, {Key: "_id", Value: 1}}).
+			SetLimit(int64(limit+1)),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
+		return
+	}
+	comments := []models.Comment{}
+	if err := cursor.All(ctx, &comments); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse comments"})
+		return
+	}
+
+	// One extra comment was fetched to know whether another page exists
+	var nextCursor *string
+	if len(comments) > limit {
+		comments = comments[:limit]
+		last := comments[limit-1]
+		encoded, err := helpers.EncodeCursor(sortName, sort.value(last), last.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build cursor"})
+			return
+		}
+		nextCursor = &encoded
+	}
+
+	response := gin.H{"comments": comments, "next_cursor": nextCursor}
+	for key, value := range extra {
+		response[key] = value
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetSongComments lists a song's top-level comments. sort=timestamp lists the
+// ones pinned to a moment, in playback order, for showing along the waveform.
+//
+//	GET /song/:song_id/comments?sort=newest|oldest|timestamp&limit=20&cursor=...
+func GetSongComments() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		song, ok := visibleSong(ctx, c, c.Param("song_id"))
+		if !ok {
+			return
+		}
+
+		filter := bson.M{
+			"song_id":   song.SongID,
+			"parent_id": bson.M{"$exists": false},
+			// Deleted comments stay only as long as they hold a thread
+			"$or": bson.A{bson.M{"deleted": bson.M{"$ne": true}}, bson.M{"reply_count": bson.M{"$gt": 0}}},
+		}
+		commentPage(c, ctx, filter, c.DefaultQuery("sort", "newest"), gin.H{"total": song.CommentCount})
+	}
+}
+
+// GetCommentReplies lists the replies to a comment, oldest first. A thread
+// whose comment is held or removed is hidden along with it.
+//
+//	GET /song/:song_id/comments/:comment_id/replies?limit=20&cursor=...
+func GetCommentReplies() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		song, ok := visibleSong(ctx, c, c.Param("song_id"))
+		if !ok {
+			return
+		}
+
+		var parent models.Comment
+		err := commentCollection.FindOne(ctx, bson.M{"comment_id": c.Param("comment_id"), "song_id": song.SongID}).Decode(&parent)
+		if err != nil && err != mongo.ErrNoDocuments {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comment"})
+			return
+		}
+		userID := c.GetString("user_id")
+		if err == mongo.ErrNoDocuments || !(parent.Status == models.CommentVisible ||
+			(parent.Status == models.CommentPending && userID != "" && parent.UserID == userID)) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+			return
+		}
+
+		filter := bson.M{"song_id": song.SongID, "parent_id": c.Param("comment_id")}
+		commentPage(c, ctx, filter, "oldest", nil)
+	}
+}
+
+// commentInput reads and checks a comment body and optional timestamp
+func commentInput(c *gin.Context, body string, timestamp *int, song models.Song) (string, bool) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "comment can't be empty"})
+		return "", false
+	}
+	if len([]rune(body)) > commentMaxLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "comment is too long", "max": commentMaxLength})
+		return "", false
+	}
+	if timestamp != nil && (*timestamp < 0 || (song.Duration > 0 && *timestamp > song.Duration)) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "timestamp must be within the song"})
+		return "", false
+	}
+	return body, true
+}
+
+// PostComment comments on a song, or replies to a comment with parent_id.
+// Replies to a reply join the same thread. Comments a moderation check holds
+// are visible only to their author until an admin approves them.
+//
+//	POST /song/:song_id/comments {"body": "...", "timestamp": 83, "parent_id": "..."}
+func PostComment() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("user_id")
+
+		var body struct {
+			Body      string  `json:"body"`
+			Timestamp *int    `json:"timestamp"`
+			ParentID  *string `json:"parent_id"`
+		}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		song, ok := visibleSong(ctx, c, c.Param("song_id"))
+		if !ok {
+			return
+		}
+		text, ok := commentInput(c, body.Body, body.Timestamp, song)
+		if !ok {
+			return
+		}
+
+		var parentID *string
+		if body.ParentID != nil && *body.ParentID != "" {
+			var parent models.Comment
+			err := commentCollection.FindOne(ctx, bson.M{"comment_id": *body.ParentID, "song_id": song.SongID}).Decode(&parent)
+			if err != nil || parent.Status == models.CommentRemoved {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Parent comment not found"})
+				return
+			}
+			if parent.ParentID != nil {
+				parentID = parent.ParentID
+			} else {
+				parentID = &parent.CommentID
+			}
+		}
+
+		now := time.Now()
+		comment := models.Comment{
+			ID:        primitive.NewObjectID(),
+			SongID:    song.SongID,
+			UserID:    userID,
+			ParentID:  parentID,
+			Body:      text,
+			Timestamp: body.Timestamp,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		comment.CommentID = comment.ID.Hex()
+		if err := moderateComment(ctx, &comment); err != nil {
+			log.Println("❌ Comment check failed:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check comment"})
+			return
+		}
+
+		if err := saveComment(ctx, models.Comment{}, comment); err != nil {
+			respondCommentSave(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{"comment": comment})
+	}
+}
+
+// EditComment lets the author change their comment's text or timestamp; the
+// moderation checks run again. Only the fields that are sent change, and a
+// null timestamp unpins the comment.
+//
+//	PATCH /comments/:comment_id {"body": "...", "timestamp": 90}
+func EditComment() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body struct {
+			Body      *string         `json:"body"`
+			Timestamp json.RawMessage `json:"timestamp"`
+		}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+		if body.Body == nil && body.Timestamp == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Nothing to update"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		before, ok := findComment(ctx, c)
+		if !ok {
+			return
+		}
+		if before.UserID != c.GetString("user_id") {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You can only edit your own comments"})
+			return
+		}
+		if before.Deleted || before.Status == models.CommentRemoved {
+			c.JSON(http.StatusConflict, gin.H{"error": "This comment can no longer be edited"})
+			return
+		}
+
+		text := before.Body
+		if body.Body != nil {
+			text = *body.Body
+		}
+		timestamp := before.Timestamp
+		if body.Timestamp != nil {
+			timestamp = nil
+			if string(body.Timestamp) != "null" {
+				var seconds int
+				if err := json.Unmarshal(body.Timestamp, &seconds); err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "timestamp must be a number of seconds"})
+					return
+				}
+				timestamp = &seconds
+			}
+		}
+
+		song, ok := visibleSong(ctx, c, before.SongID)
+		if !ok {
+			return
+		}
+		text, ok = commentInput(c, text, timestamp, song)
+		if !ok {
+			return
+		}
+
+		now := time.Now()
+		after := before
+		after.Body = text
+		after.Timestamp = timestamp
+		after.EditedAt = &now
+		after.UpdatedAt = now
+		if err := moderateComment(ctx, &after); err != nil {
+			log.Println("❌ Comment check failed:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check comment"})
+			return
+		}
+		// An edit doesn't clear a hold from reports; only an admin does
+		if before.Status == models.CommentPending && after.Status == models.CommentVisible && len(before.ReportedBy) >= commentReportThreshold {
+			after.Status = models.CommentPending
+			after.ModerationReason = before.ModerationReason
+		}
+
+		if err := saveComment(ctx, before, after); err != nil {
+			respondCommentSave(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"comment": after})
+	}
+}
+
+// DeleteComment deletes a comment (author or admin). A comment with replies
+// is blanked and kept so the thread still reads.
+//
+//	DELETE /comments/:comment_id
+func DeleteComment() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		before, ok := findComment(ctx, c)
+		if !ok {
+			return
+		}
+		if before.UserID != c.GetString("user_id") && c.GetString("user_type") != "ADMIN" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You can only delete your own comments"})
+			return
+		}
+		if before.Deleted {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+			return
+		}
+
+		if before.ParentID == nil && before.ReplyCount > 0 {
+			after := before
+			after.Body = ""
+			after.Timestamp = nil
+			after.Deleted = true
+			after.UpdatedAt = time.Now()
+			if err := saveComment(ctx, before, after); err != nil {
+				respondCommentSave(c, err)
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{"message": "Comment deleted"})
+			return
+		}
+
+		result, err := commentCollection.DeleteOne(ctx, bson.M{"comment_id": before.CommentID, "updated_at": before.UpdatedAt})
+		if err == nil && result.DeletedCount == 0 {
+			err = errCommentChanged
+		}
+		// Held or removed replies go with it
+		if err == nil && before.ParentID == nil {
+			_, err = commentCollection.DeleteMany(ctx, bson.M{"parent_id": before.CommentID})
+		}
+		if err == nil {
+			err = adjustCommentCounts(ctx, before, -countOf(before))
+		}
+		if err != nil {
+			respondCommentSave(c, err)
+			return
+		}
+
+		// A blanked parent whose last reply just went has nothing left to hold
+		if before.ParentID != nil {
+			_, _ = commentCollection.DeleteOne(ctx, bson.M{"comment_id": *before.ParentID, "deleted": true, "reply_count": bson.M{"$lte": 0}})
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Comment deleted"})
+	}
+}
+
+// ReportComment flags a comment; enough reports hide it until an admin looks
+//
+//	POST /comments/:comment_id/report
+func ReportComment() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("user_id")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		before, ok := findComment(ctx, c)
+		if !ok {
+			return
+		}
+		if before.UserID == userID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "You can't report your own comment"})
+			return
+		}
+		if before.Deleted || before.Status == models.CommentRemoved || containsString(before.ReportedBy, userID) {
+			c.JSON(http.StatusOK, gin.H{"message": "Comment reported"})
+			return
+		}
+
+		after := before
+		after.ReportedBy = append(append([]string{}, before.ReportedBy...), userID)
+		after.UpdatedAt = time.Now()
+		if after.Status == models.CommentVisible && len(after.ReportedBy) >= commentReportThreshold {
+			reason := "reported by listeners"
+			after.Status = models.CommentPending
+			after.ModerationReason = &reason
+		}
+		if err := saveComment(ctx, before, after); err != nil {
+			respondCommentSave(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Comment reported"})
+	}
+}
+
+// GetCommentQueue lists comments held for review, oldest first (Admin only)
+//
+//	GET /admin/comments?status=pending
+func GetCommentQueue() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		status := models.CommentStatus(c.DefaultQuery("status", string(models.CommentPending)))
+		if status != models.CommentPending && status != models.CommentRemoved {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status, use one of: pending | removed"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		cursor, err := commentCollection.Find(ctx,
+			bson.M{"status": status},
+			options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}).SetLimit(100),
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
+			return
+		}
+		comments := []models.Comment{}
+		if err := cursor.All(ctx, &comments); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse comments"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"status": status, "comments": comments, "count": len(comments)})
+	}
+}
+
+// ApproveComment makes a held or removed comment visible and clears its reports (Admin only)
+func ApproveComment() gin.HandlerFunc {
+	return reviewComment(models.CommentVisible)
+}
+
+// RemoveComment takes a comment down and records why (Admin only)
+func RemoveComment() gin.HandlerFunc {
+	return reviewComment(models.CommentRemoved)
+}
+
+// reviewComment builds the handler shared by approve/remove
+func reviewComment(status models.CommentStatus) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body struct {
+			Reason string `json:"reason"`
+		}
+		// Body is optional for approve
+		_ = c.ShouldBindJSON(&body)
+
+		reason := strings.TrimSpace(body.Reason)
+		if status == models.CommentRemoved && reason == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "reason is required"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		before, ok := findComment(ctx, c)
+		if !ok {
+			return
+		}
+
+		after := before
+		after.Status = status
+		after.ModerationReason = nil
+		if reason != "" {
+			after.ModerationReason = &reason
+		}
+		if status == models.CommentVisible {
+			after.ReportedBy = nil
+		}
+		after.UpdatedAt = time.Now()
+		if err := saveComment(ctx, before, after); err != nil {
+			respondCommentSave(c, err)
+			return
+		}
+
+		log.Printf("✅ Comment %s marked %s by %s\n", before.CommentID, status, c.GetString("user_id"))
+		c.JSON(http.StatusOK, gin.H{"message": "Comment status updated", "comment": after})
+	}
+}
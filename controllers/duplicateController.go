@@ -0,0 +1,211 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ishanbagra18/ecommerce-using-go/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetDuplicateCandidates lists songs flagged as near-duplicates on upload (Admin only)
+func GetDuplicateCandidates() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log.Println("🔹 GetDuplicateCandidates endpoint hit")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+		cursor, err := songcollection.Find(ctx, bson.M{"needs_review": true}, opts)
+		if err != nil {
+			log.Println("❌ Failed to fetch duplicate candidates:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch duplicate candidates"})
+			return
+		}
+		defer cursor.Close(ctx)
+
+		var songs []models.Song
+		if err := cursor.All(ctx, &songs); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse songs"})
+			return
+		}
+
+		if songs == nil {
+			songs = []models.Song{}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"songs": songs,
+			"count": len(songs),
+		})
+	}
+}
+
+// DismissDuplicate clears the near-duplicate flag when an admin decides the songs are different
+func DismissDuplicate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		songID := c.Param("song_id")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		update := bson.M{
+			"$set":   bson.M{"needs_review": false, "updated_at": time.Now()},
+			"$unset": bson.M{"duplicate_of": ""},
+		}
+
+		result, err := songcollection.UpdateOne(ctx, bson.M{"song_id": songID}, update)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update song"})
+			return
+		}
+
+		if result.MatchedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Song not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Duplicate flag dismissed"})
+	}
+}
+
+// MergeSongs folds a duplicate song into the surviving one (Admin only).
+// Likes, saves, play counts, history, playlist entries and everything else
+// pointing at the duplicate are moved onto target_song_id and the duplicate
+// is removed. A merge that failed halfway can simply be retried.
+func MergeSongs() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log.Println("🔹 MergeSongs endpoint hit")
+
+		var body struct {
+			SourceSongID string `json:"source_song_id" binding:"required"`
+			TargetSongID string `json:"target_song_id" binding:"required"`
+		}
+
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "source_song_id and target_song_id are required"})
+			return
+		}
+
+		if body.SourceSongID == body.TargetSongID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot merge a song into itself"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		var source, target models.Song
+		if err := songcollection.FindOne(ctx, bson.M{"song_id": body.SourceSongID}).Decode(&source); err != nil {
+			if err == mongo.ErrNoDocuments {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Source song not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch source song"})
+			return
+		}
+		if err := songcollection.FindOne(ctx, bson.M{"song_id": body.TargetSongID}).Decode(&target); err != nil {
+			if err == mongo.ErrNoDocuments {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Target song not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch target song"})
+			return
+		}
+
+		// A retry must merge into the same song; the mark also tells a second
+		// admin the duplicate is already on its way out
+		if source.MergedInto != nil && *source.MergedInto != target.SongID {
+			c.JSON(http.StatusConflict, gin.H{"error": "Source song is already being merged into " + *source.MergedInto})
+			return
+		}
+		if target.MergedInto != nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "Target song is itself being merged away"})
+			return
+		}
+		if _, err := songcollection.UpdateOne(ctx,
+			bson.M{"song_id": source.SongID},
+			bson.M{"$set": bson.M{"merged_into": target.SongID}},
+		); err != nil {
+			log.Println("❌ Failed to mark song as merging:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to merge songs"})
+			return
+		}
+
+		// 1️⃣ Every reference to the duplicate. Each step only finds what is
+		// still left on the source, so a retry after a failure picks up where it stopped.
+		historyMoved, playlistsUpdated, err := moveSongReferences(ctx, source, target)
+		if err != nil {
+			log.Println("❌ Failed to move song references:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to move references to the song"})
+			return
+		}
+
+		// 2️⃣ Likes, saves and play counts, last and only once: merged_from
+		// records that this source was already added to the target
+		inc := bson.M{"play_count": source.PlayCount, "comment_count": source.CommentCount}
+		for userID, plays := range source.UserPlayCounts {
+			inc["user_play_counts."+userID] = plays
+		}
+
+		set := bson.M{"updated_at": time.Now()}
+		// The survivor inherits the duplicate's album slot if it had none
+		if target.AlbumID == nil && source.AlbumID != nil {
+			set["album_id"] = *source.AlbumID
+			set["album"] = source.Album
+		}
+		// ...and its credits and lyrics the same way
+		if target.Credits == nil && source.Credits != nil {
+			set["credits"] = source.Credits
+		}
+		if !target.HasLyrics && source.HasLyrics {
+			set["has_lyrics"] = true
+			set["has_synced_lyrics"] = source.HasSynced
+		}
+		addToSet := bson.M{"merged_from": source.SongID}
+		if len(source.Likes) > 0 {
+			addToSet["likes"] = bson.M{"$each": source.Likes}
+		}
+		if len(source.Saves) > 0 {
+			addToSet["saves"] = bson.M{"$each": source.Saves}
+		}
+
+		if _, err := songcollection.UpdateOne(ctx,
+			bson.M{"song_id": target.SongID, "merged_from": bson.M{"$ne": source.SongID}},
+			bson.M{"$inc": inc, "$set": set, "$addToSet": addToSet},
+		); err != nil {
+			log.Println("❌ Failed to merge counters:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to merge likes and play counts"})
+			return
+		}
+
+		// Users who liked both count once
+		if _, err := recountReactions(ctx, bson.M{"song_id": target.SongID}); err != nil {
+			log.Println("❌ Failed to recount likes:", err)
+		}
+
+		// 3️⃣ Remove the duplicate
+		if _, err := songcollection.DeleteOne(ctx, bson.M{"song_id": source.SongID}); err != nil {
+			log.Println("❌ Failed to delete merged song:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete merged song"})
+			return
+		}
+		unindexSong(source.SongID)
+
+		log.Printf("✅ Merged song %s into %s\n", source.SongID, target.SongID)
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":           "Songs merged successfully",
+			"song_id":           target.SongID,
+			"history_moved":     historyMoved,
+			"playlists_updated": playlistsUpdated,
+		})
+	}
+}
@@ -0,0 +1,799 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ishanbagra18/ecommerce-using-go/database"
+	"github.com/ishanbagra18/ecommerce-using-go/helpers"
+	"github.com/ishanbagra18/ecommerce-using-go/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var genreCollection *mongo.Collection
+var genreRequestCollection *mongo.Collection
+
+var errUnknownGenre = errors.New("unknown genre")
+
+// Moods the taxonomy starts with; admins can add more
+var defaultMoods = []string{"Chill", "Workout", "Party", "Romantic", "Sad", "Focus", "Sleep", "Devotional"}
+
+func InitGenreController() {
+	genreCollection = database.OpenCollection(database.Client, "genres")
+	log.Println("✔ Genre collection initialized")
+
+	database.CreateIndexes(genreCollection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "slug", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "aliases", Value: 1}}},
+		{Keys: bson.D{{Key: "kind", Value: 1}, {Key: "parent", Value: 1}}},
+	})
+
+	genreRequestCollection = database.OpenCollection(database.Client, "genre_requests")
+	database.CreateIndexes(genreRequestCollection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "slug", Value: 1}}, Options: options.Index().SetUnique(true)},
+	})
+}
+
+// findGenre looks a name or slug up by its slug or any alias
+func findGenre(ctx context.Context, name string, kind models.GenreKind) (*models.Genre, error) {
+	slug := helpers.Slugify(name)
+	if slug == "" {
+		return nil, nil
+	}
+
+	var genre models.Genre
+	err := genreCollection.FindOne(ctx, bson.M{
+		"kind": kind,
+		"$or":  bson.A{bson.M{"slug": slug}, bson.M{"aliases": slug}},
+	}).Decode(&genre)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &genre, nil
+}
+
+// normalizeGenre maps a free-text genre onto the taxonomy, so "Pop", "pop"
+// and "POP " all become the same genre. Genres the taxonomy doesn't have
+// return nil and are queued for an admin to add; the taxonomy only grows
+// through admins.
+func normalizeGenre(ctx context.Context, name string) (*models.Genre, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	genre, err := findGenre(ctx, name, models.GenreKindGenre)
+	if err != nil || genre != nil || name == "" {
+		return genre, err
+	}
+	return nil, requestGenre(ctx, name)
+}
+
+// requestGenre records that an uploader used a genre the taxonomy doesn't have
+func requestGenre(ctx context.Context, name string) error {
+	slug := helpers.Slugify(name)
+	if slug == "" {
+		return nil
+	}
+	now := time.Now()
+	_, err := genreRequestCollection.UpdateOne(ctx,
+		bson.M{"slug": slug},
+		bson.M{
+			"$addToSet":    bson.M{"names": name},
+			"$inc":         bson.M{"uses": 1},
+			"$set":         bson.M{"updated_at": now},
+			"$setOnInsert": bson.M{"created_at": now},
+		},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		// Two first requests at once; the other one recorded it
+		return nil
+	}
+	return err
+}
+
+// findOrCreateGenre is normalizeGenre for the data migration that built the
+// taxonomy: genres nobody has used yet are added at the top level
+func findOrCreateGenre(ctx context.Context, name string) (*models.Genre, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	genre, err := findGenre(ctx, name, models.GenreKindGenre)
+	if err != nil || genre != nil || name == "" {
+		return genre, err
+	}
+
+	now := time.Now()
+	genre = &models.Genre{
+		ID:        primitive.NewObjectID(),
+		Slug:      helpers.Slugify(name),
+		Name:      name,
+		Kind:      models.GenreKindGenre,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if _, err := genreCollection.InsertOne(ctx, genre); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			// Created by another request in the meantime
+			return findGenre(ctx, name, models.GenreKindGenre)
+		}
+		return nil, err
+	}
+	return genre, nil
+}
+
+// normalizeGenreNames maps a list of genres onto their taxonomy names, as
+// stored on artists. Unknown genres keep their name and are queued for an admin.
+func normalizeGenreNames(ctx context.Context, names []string) ([]string, error) {
+	normalized := []string{}
+	for _, name := range names {
+		genre, err := normalizeGenre(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		name = strings.Join(strings.Fields(name), " ")
+		if genre != nil {
+			name = genre.Name
+		}
+		if name != "" && !containsString(normalized, name) {
+			normalized = append(normalized, name)
+		}
+	}
+	return normalized, nil
+}
+
+// resolveMoods maps mood names to slugs. Moods are curated, so unknown ones are rejected.
+func resolveMoods(ctx context.Context, names []string) ([]string, error) {
+	slugs := []string{}
+	for _, name := range names {
+		mood, err := findGenre(ctx, name, models.GenreKindMood)
+		if err != nil {
+			return nil, err
+		}
+		if mood == nil {
+			return nil, fmt.Errorf("%w: no mood called %q", errUnknownGenre, name)
+		}
+		if !containsString(slugs, mood.Slug) {
+			slugs = append(slugs, mood.Slug)
+		}
+	}
+	return slugs, nil
+}
+
+// genreSlugsWithDescendants resolves genre names to slugs, adding every
+// genre below them, so browsing "pop" includes "punjabi-pop". Names that
+// aren't in the taxonomy are kept as slugs and simply match nothing.
+func genreSlugsWithDescendants(ctx context.Context, names []string) ([]string, error) {
+	cursor, err := genreCollection.Find(ctx,
+		bson.M{"kind": models.GenreKindGenre, "parent": bson.M{"$exists": true}},
+		options.Find().SetProjection(bson.M{"slug": 1, "parent": 1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var children []models.Genre
+	if err := cursor.All(ctx, &children); err != nil {
+		return nil, err
+	}
+	childrenOf := make(map[string][]string)
+	for _, child := range children {
+		childrenOf[*child.Parent] = append(childrenOf[*child.Parent], child.Slug)
+	}
+
+	var slugs []string
+	for _, name := range names {
+		slug := helpers.Slugify(name)
+		genre, err := findGenre(ctx, name, models.GenreKindGenre)
+		if err != nil {
+			return nil, err
+		}
+		if genre != nil {
+			slug = genre.Slug
+		}
+
+		queue := []string{slug}
+		for len(queue) > 0 {
+			next := queue[0]
+			queue = queue[1:]
+			if containsString(slugs, next) {
+				continue
+			}
+			slugs = append(slugs, next)
+			queue = append(queue, childrenOf[next]...)
+		}
+	}
+	return slugs, nil
+}
+
+// isGenreAncestor reports whether ancestor sits above slug in the tree
+func isGenreAncestor(ctx context.Context, ancestor string, slug string) (bool, error) {
+	for depth := 0; slug != "" && depth < 32; depth++ {
+		var genre models.Genre
+		err := genreCollection.FindOne(ctx, bson.M{"slug": slug}).Decode(&genre)
+		if err == mongo.ErrNoDocuments {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if genre.Parent == nil {
+			return false, nil
+		}
+		if *genre.Parent == ancestor {
+			return true, nil
+		}
+		slug = *genre.Parent
+	}
+	return false, nil
+}
+
+type genreNode struct {
+	models.Genre
+	Children []*genreNode `json:"children"`
+}
+
+// GetGenres returns the genre tree, or the list of moods with ?kind=mood
+//
+//	GET /genres?kind=genre|mood
+func GetGenres() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		kind := models.GenreKind(c.DefaultQuery("kind", string(models.GenreKindGenre)))
+		if !models.IsValidGenreKind(kind) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "kind must be genre or mood"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		cursor, err := genreCollection.Find(ctx, bson.M{"kind": kind}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch genres"})
+			return
+		}
+		var genres []models.Genre
+		if err := cursor.All(ctx, &genres); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse genres"})
+			return
+		}
+
+		nodes := make(map[string]*genreNode, len(genres))
+		for _, genre := range genres {
+			nodes[genre.Slug] = &genreNode{Genre: genre, Children: []*genreNode{}}
+		}
+		roots := []*genreNode{}
+		for _, genre := range genres {
+			node := nodes[genre.Slug]
+			if genre.Parent != nil {
+				if parent, ok := nodes[*genre.Parent]; ok {
+					parent.Children = append(parent.Children, node)
+					continue
+				}
+			}
+			roots = append(roots, node)
+		}
+
+		c.JSON(http.StatusOK, gin.H{"kind": kind, "genres": roots})
+	}
+}
+
+// GetGenre returns one genre or mood with its parent and direct children.
+// Aliases resolve too, so /genres/hiphop finds "hip-hop".
+//
+//	GET /genres/:slug
+func GetGenre() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		slug := helpers.Slugify(c.Param("slug"))
+		var genre models.Genre
+		err := genreCollection.FindOne(ctx, bson.M{"$or": bson.A{bson.M{"slug": slug}, bson.M{"aliases": slug}}}).Decode(&genre)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Genre not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch genre"})
+			return
+		}
+
+		var parent *models.Genre
+		if genre.Parent != nil {
+			var found models.Genre
+			if err := genreCollection.FindOne(ctx, bson.M{"slug": *genre.Parent}).Decode(&found); err == nil {
+				parent = &found
+			}
+		}
+
+		cursor, err := genreCollection.Find(ctx, bson.M{"parent": genre.Slug}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch genres"})
+			return
+		}
+		children := []models.Genre{}
+		if err := cursor.All(ctx, &children); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse genres"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"genre": genre, "parent": parent, "children": children})
+	}
+}
+
+// GetGenreSongs browses the songs of a genre and every genre below it, or of
+// a mood. It takes the same filters, sorts and cursor as /music/songs.
+//
+//	GET /genres/:slug/songs?language=punjabi&sort=most_played&cursor=...
+func GetGenreSongs() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		slug := helpers.Slugify(c.Param("slug"))
+		var genre models.Genre
+		err := genreCollection.FindOne(ctx, bson.M{"$or": bson.A{bson.M{"slug": slug}, bson.M{"aliases": slug}}}).Decode(&genre)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Genre not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch genre"})
+			return
+		}
+
+		if genre.Kind == models.GenreKindMood {
+			browseSongs(c, bson.M{"moods": genre.Slug})
+			return
+		}
+
+		slugs, err := genreSlugsWithDescendants(ctx, []string{genre.Slug})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch genres"})
+			return
+		}
+		browseSongs(c, bson.M{"genre_slug": bson.M{"$in": slugs}})
+	}
+}
+
+// CreateGenre adds a genre or mood to the taxonomy (admin only)
+//
+//	POST /admin/genres {"name": "Punjabi Pop", "kind": "genre", "parent": "pop", "aliases": ["Pollywood Pop"]}
+func CreateGenre() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body struct {
+			Name    string           `json:"name" binding:"required,min=2,max=50"`
+			Kind    models.GenreKind `json:"kind"`
+			Parent  string           `json:"parent"`
+			Aliases []string         `json:"aliases"`
+		}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "name is required (2-50 characters)"})
+			return
+		}
+		if body.Kind == "" {
+			body.Kind = models.GenreKindGenre
+		}
+		if !models.IsValidGenreKind(body.Kind) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "kind must be genre or mood"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		name := strings.Join(strings.Fields(body.Name), " ")
+		now := time.Now()
+		genre := models.Genre{
+			ID:        primitive.NewObjectID(),
+			Slug:      helpers.Slugify(name),
+			Name:      name,
+			Kind:      body.Kind,
+			Aliases:   genreAliases(body.Aliases),
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+
+		if existing, err := findGenre(ctx, name, body.Kind); err != nil || existing != nil {
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check genres"})
+				return
+			}
+			c.JSON(http.StatusConflict, gin.H{"error": "This genre already exists", "slug": existing.Slug})
+			return
+		}
+
+		if body.Parent != "" {
+			if body.Kind == models.GenreKindMood {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "moods can't have a parent"})
+				return
+			}
+			parent, err := findGenre(ctx, body.Parent, models.GenreKindGenre)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch parent genre"})
+				return
+			}
+			if parent == nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Parent genre not found"})
+				return
+			}
+			genre.Parent = &parent.Slug
+		}
+
+		if _, err := genreCollection.InsertOne(ctx, genre); err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				c.JSON(http.StatusConflict, gin.H{"error": "This genre already exists", "slug": genre.Slug})
+				return
+			}
+			log.Println("❌ Failed to create genre:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create genre"})
+			return
+		}
+
+		if err := adoptGenreRequests(ctx, genre); err != nil {
+			log.Printf("⚠️ Failed to link requested genres to %s: %v\n", genre.Slug, err)
+		}
+
+		c.JSON(http.StatusCreated, gin.H{"message": "Genre created", "genre": genre})
+	}
+}
+
+// UpdateGenre renames a genre, moves it under another parent ("" for the top
+// level) or replaces its aliases (admin only). Renaming updates the songs and
+// artists that show the old name.
+//
+//	PATCH /admin/genres/:slug {"name": "Hip-Hop", "parent": "", "aliases": ["rap"]}
+func UpdateGenre() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body struct {
+			Name    *string   `json:"name"`
+			Parent  *string   `json:"parent"`
+			Aliases *[]string `json:"aliases"`
+		}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		var genre models.Genre
+		if err := genreCollection.FindOne(ctx, bson.M{"slug": c.Param("slug")}).Decode(&genre); err != nil {
+			if err == mongo.ErrNoDocuments {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Genre not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch genre"})
+			return
+		}
+
+		set := bson.M{"updated_at": time.Now()}
+		unset := bson.M{}
+
+		// The slug stays put so links keep working; only the display name changes
+		if body.Name != nil {
+			name := strings.Join(strings.Fields(*body.Name), " ")
+			if len(name) < 2 || len(name) > 50 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "name must be 2-50 characters"})
+				return
+			}
+			set["name"] = name
+		}
+
+		if body.Parent != nil {
+			switch {
+			case *body.Parent == "":
+				unset["parent"] = ""
+			case genre.Kind == models.GenreKindMood:
+				c.JSON(http.StatusBadRequest, gin.H{"error": "moods can't have a parent"})
+				return
+			default:
+				parent, err := findGenre(ctx, *body.Parent, models.GenreKindGenre)
+				if err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch parent genre"})
+					return
+				}
+				if parent == nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "Parent genre not found"})
+					return
+				}
+				cycle, err := isGenreAncestor(ctx, genre.Slug, parent.Slug)
+				if err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check genre tree"})
+					return
+				}
+				if parent.Slug == genre.Slug || cycle {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "A genre can't be placed under itself"})
+					return
+				}
+				set["parent"] = parent.Slug
+			}
+		}
+
+		if body.Aliases != nil {
+			set["aliases"] = genreAliases(*body.Aliases)
+		}
+
+		update := bson.M{"$set": set}
+		if len(unset) > 0 {
+			update["$unset"] = unset
+		}
+		if _, err := genreCollection.UpdateOne(ctx, bson.M{"slug": genre.Slug}, update); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update genre"})
+			return
+		}
+
+		if name, ok := set["name"].(string); ok && name != genre.Name && genre.Kind == models.GenreKindGenre {
+			if err := renameGenreReferences(ctx, genre, name); err != nil {
+				log.Printf("⚠️ Failed to rename genre %s on songs and artists: %v\n", genre.Slug, err)
+			}
+		}
+
+		var updated models.Genre
+		if err := genreCollection.FindOne(ctx, bson.M{"slug": genre.Slug}).Decode(&updated); err != nil {
+			c.JSON(http.StatusOK, gin.H{"message": "Genre updated"})
+			return
+		}
+		if body.Aliases != nil {
+			if err := adoptGenreRequests(ctx, updated); err != nil {
+				log.Printf("⚠️ Failed to link requested genres to %s: %v\n", updated.Slug, err)
+			}
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Genre updated", "genre": updated})
+	}
+}
+
+// MergeGenre folds one genre or mood into another (admin only). Songs,
+// artists and child genres move over and the merged slug becomes an alias.
+//
+//	POST /admin/genres/:slug/merge {"into": "pop"}
+func MergeGenre() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body struct {
+			Into string `json:"into" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "into is required"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		var source models.Genre
+		if err := genreCollection.FindOne(ctx, bson.M{"slug": c.Param("slug")}).Decode(&source); err != nil {
+			if err == mongo.ErrNoDocuments {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Genre not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch genre"})
+			return
+		}
+
+		target, err := findGenre(ctx, body.Into, source.Kind)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch genre"})
+			return
+		}
+		if target == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Target " + string(source.Kind) + " not found"})
+			return
+		}
+		if target.Slug == source.Slug {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Can't merge a genre into itself"})
+			return
+		}
+
+		// 1️⃣ Songs
+		var songsMoved int64
+		if source.Kind == models.GenreKindMood {
+			if _, err := songcollection.UpdateMany(ctx,
+				bson.M{"moods": source.Slug},
+				bson.M{"$addToSet": bson.M{"moods": target.Slug}},
+			); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to move songs"})
+				return
+			}
+			result, err := songcollection.UpdateMany(ctx,
+				bson.M{"moods": source.Slug},
+				bson.M{"$pull": bson.M{"moods": source.Slug}},
+			)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to move songs"})
+				return
+			}
+			songsMoved = result.ModifiedCount
+		} else {
+			result, err := songcollection.UpdateMany(ctx,
+				bson.M{"genre_slug": source.Slug},
+				bson.M{"$set": bson.M{"genre_slug": target.Slug, "genre": target.Name}},
+			)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to move songs"})
+				return
+			}
+			songsMoved = result.ModifiedCount
+
+			// 2️⃣ Artists
+			if _, err := artistCollection.UpdateMany(ctx,
+				bson.M{"genre": source.Name},
+				bson.M{"$addToSet": bson.M{"genre": target.Name}},
+			); err != nil {
+				log.Println("❌ Failed to move artist genres:", err)
+			}
+			if _, err := artistCollection.UpdateMany(ctx,
+				bson.M{"genre": source.Name},
+				bson.M{"$pull": bson.M{"genre": source.Name}},
+			); err != nil {
+				log.Println("❌ Failed to move artist genres:", err)
+			}
+
+			// 3️⃣ Children move up to the target; a target that was a child
+			// of the merged genre takes its place in the tree
+			if _, err := genreCollection.UpdateMany(ctx,
+				bson.M{"parent": source.Slug, "slug": bson.M{"$ne": target.Slug}},
+				bson.M{"$set": bson.M{"parent": target.Slug, "updated_at": time.Now()}},
+			); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to move child genres"})
+				return
+			}
+			if target.Parent != nil && *target.Parent == source.Slug {
+				update := bson.M{"$unset": bson.M{"parent": ""}}
+				if source.Parent != nil {
+					update = bson.M{"$set": bson.M{"parent": *source.Parent}}
+				}
+				if _, err := genreCollection.UpdateOne(ctx, bson.M{"slug": target.Slug}, update); err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to move genre"})
+					return
+				}
+			}
+		}
+
+		// 4️⃣ The old slug and its aliases keep resolving, now to the target
+		aliases := append([]string{source.Slug}, source.Aliases...)
+		if _, err := genreCollection.UpdateOne(ctx,
+			bson.M{"slug": target.Slug},
+			bson.M{
+				"$addToSet": bson.M{"aliases": bson.M{"$each": aliases}},
+				"$set":      bson.M{"updated_at": time.Now()},
+			},
+		); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update genre"})
+			return
+		}
+		if _, err := genreCollection.DeleteOne(ctx, bson.M{"slug": source.Slug}); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete merged genre"})
+			return
+		}
+
+		log.Printf("🔀 Merged %s %s into %s (%d songs)\n", source.Kind, source.Slug, target.Slug, songsMoved)
+		c.JSON(http.StatusOK, gin.H{
+			"message":     "Genres merged",
+			"merged":      source.Slug,
+			"into":        target.Slug,
+			"songs_moved": songsMoved,
+		})
+	}
+}
+
+// adoptGenreRequests links the songs and artists waiting on requested genres
+// that now resolve to genre, by its slug or an alias, and closes the requests
+func adoptGenreRequests(ctx context.Context, genre models.Genre) error {
+	if genre.Kind != models.GenreKindGenre {
+		return nil
+	}
+	cursor, err := genreRequestCollection.Find(ctx, bson.M{"slug": bson.M{"$in": append([]string{genre.Slug}, genre.Aliases...)}})
+	if err != nil {
+		return err
+	}
+	var requests []models.GenreRequest
+	if err := cursor.All(ctx, &requests); err != nil {
+		return err
+	}
+
+	for _, request := range requests {
+		if _, err := songcollection.UpdateMany(ctx,
+			bson.M{"genre": bson.M{"$in": request.Names}, "genre_slug": nil},
+			bson.M{"$set": bson.M{"genre": genre.Name, "genre_slug": genre.Slug}},
+		); err != nil {
+			return err
+		}
+		if _, err := artistCollection.UpdateMany(ctx,
+			bson.M{"genre": bson.M{"$in": request.Names}},
+			bson.M{"$set": bson.M{"genre.$[g]": genre.Name}},
+			options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{bson.M{"g": bson.M{"$in": request.Names}}}}),
+		); err != nil {
+			return err
+		}
+		if _, err := genreRequestCollection.DeleteOne(ctx, bson.M{"slug": request.Slug}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetGenreRequests lists the genres uploaders used that the taxonomy doesn't
+// have, most used first (admin only). Adding the genre, or an alias for it,
+// links the waiting songs.
+//
+//	GET /admin/genres/requests
+func GetGenreRequests() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		cursor, err := genreRequestCollection.Find(ctx, bson.M{},
+			options.Find().SetSort(bson.D{{Key: "uses", Value: -1}, {Key: "created_at", Value: 1}}).SetLimit(100),
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch genre requests"})
+			return
+		}
+		requests := []models.GenreRequest{}
+		if err := cursor.All(ctx, &requests); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse genre requests"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"requests": requests, "count": len(requests)})
+	}
+}
+
+// DismissGenreRequest drops a requested genre (admin only). Its songs keep
+// the name as typed without a genre link.
+//
+//	DELETE /admin/genres/requests/:slug
+func DismissGenreRequest() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		result, err := genreRequestCollection.DeleteOne(ctx, bson.M{"slug": c.Param("slug")})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to dismiss genre request"})
+			return
+		}
+		if result.DeletedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Genre request not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Genre request dismissed"})
+	}
+}
+
+// renameGenreReferences updates the genre name shown on songs and artists
+func renameGenreReferences(ctx context.Context, genre models.Genre, name string) error {
+	if _, err := songcollection.UpdateMany(ctx,
+		bson.M{"genre_slug": genre.Slug},
+		bson.M{"$set": bson.M{"genre": name}},
+	); err != nil {
+		return err
+	}
+	_, err := artistCollection.UpdateMany(ctx,
+		bson.M{"genre": genre.Name},
+		bson.M{"$set": bson.M{"genre.$[g]": name}},
+		options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{bson.M{"g": genre.Name}}}),
+	)
+	return err
+}
+
+// genreAliases slugifies aliases and drops blanks and repeats
+func genreAliases(names []string) []string {
+	aliases := []string{}
+	for _, name := range names {
+		if slug := helpers.Slugify(name); slug != "" && !containsString(aliases, slug) {
+			aliases = append(aliases, slug)
+		}
+	}
+	return aliases
+}
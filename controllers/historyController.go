@@ -17,6 +17,11 @@ var historyCollection *mongo.Collection
 
 func InitHistoryController() {
 	historyCollection = database.OpenCollection(database.Client, "history")
+
+	database.CreateIndexes(historyCollection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "played_at", Value: -1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "played_at", Value: -1}}},
+	})
 }
 
 // controllers/history.go
@@ -36,7 +41,7 @@ func GetMyHistory() gin.HandlerFunc {
 
 		cursor, err := historyCollection.Find(
 			context.Background(),
-			bson.M{"user_id": userID.(string)},
+			bson.M{"user_id": userID.(string), "counted": true},
 			opts,
 		)
 		if err != nil {
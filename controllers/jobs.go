@@ -0,0 +1,28 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// startPeriodicJob runs job once straight away and then every interval, in the background.
+// Each run gets its own timeout so one stuck run can't block the next.
+func startPeriodicJob(name string, interval time.Duration, timeout time.Duration, job func(ctx context.Context) error) {
+	go func() {
+		log.Printf("✔ Job %s started (every %s)\n", name, interval)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			if err := job(ctx); err != nil {
+				log.Printf("❌ Job %s failed: %v\n", name, err)
+			}
+			cancel()
+
+			<-ticker.C
+		}
+	}()
+}
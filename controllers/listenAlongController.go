@@ -0,0 +1,451 @@
+package controllers
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/ishanbagra18/ecommerce-using-go/helpers"
+	"github.com/ishanbagra18/ecommerce-using-go/listenalong"
+	"github.com/ishanbagra18/ecommerce-using-go/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	inviteAlphabet        = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789" // no 0/O or 1/I to misread
+	inviteLength          = 6
+	listenMaxParticipants = 50
+	listenIdleTimeout     = 6 * time.Hour
+)
+
+var (
+	listenStore listenalong.Store = listenalong.NewMemoryStore()
+	listenHub                     = listenalong.NewHub()
+
+	errSessionFull = errors.New("session is full")
+	errNotHost     = errors.New("only the host controls playback")
+)
+
+var socketUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     allowedSocketOrigin,
+}
+
+// allowedSocketOrigin accepts the same browser origins as CORS, and clients
+// that send no origin at all
+func allowedSocketOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	allowed := os.Getenv("CORS_ORIGINS")
+	if allowed == "" {
+		allowed = "http://localhost:5173"
+	}
+	for _, o := range strings.Split(allowed, ",") {
+		if strings.TrimSpace(o) == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// StartListenAlongExpiry ends sessions nobody has touched for listenIdleTimeout
+func StartListenAlongExpiry() {
+	startPeriodicJob("listen-along-expiry", 10*time.Minute, time.Minute, func(ctx context.Context) error {
+		expired, err := listenStore.Expire(ctx, time.Now().Add(-listenIdleTimeout))
+		if err != nil {
+			return err
+		}
+		for _, sessionID := range expired {
+			listenHub.Disconnect(sessionID, "", listenEvent(models.ListenEventEnded, ""))
+		}
+		return nil
+	})
+}
+
+func newInviteCode() (string, error) {
+	code := make([]byte, inviteLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(inviteAlphabet))))
+		if err != nil {
+			return "", err
+		}
+		code[i] = inviteAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+func listenEvent(eventType models.ListenEventType, userID string) models.ListenEvent {
+	return models.ListenEvent{Type: eventType, UserID: userID, ServerTime: time.Now().UnixMilli()}
+}
+
+// stateEvent carries the whole session and where playback is right now
+func stateEvent(session models.ListenSession) models.ListenEvent {
+	event := listenEvent(models.ListenEventState, "")
+	event.SongID = session.SongID
+	event.IsPlaying = session.IsPlaying
+	event.Position = session.Position
+	if session.IsPlaying {
+		event.Position += time.Since(session.UpdatedAt).Seconds()
+	}
+	event.Session = &session
+	return event
+}
+
+// findListenSession loads a session the logged-in user is part of
+func findListenSession(ctx context.Context, c *gin.Context) (models.ListenSession, bool) {
+	session, err := listenStore.Get(ctx, c.Param("session_id"))
+	if err == nil && !containsString(session.Participants, c.GetString("user_id")) {
+		err = listenalong.ErrNotFound
+	}
+	if err != nil {
+		if err == listenalong.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return session, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch session"})
+		return session, false
+	}
+	return session, true
+}
+
+// CreateListenSession starts a group session hosted by the logged-in user,
+// picking up from what they are playing now
+//
+//	POST /listen-along
+func CreateListenSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("user_id")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		playback, err := loadPlaybackState(ctx, userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch playback state"})
+			return
+		}
+
+		now := time.Now()
+		session := models.ListenSession{
+			SessionID:    primitive.NewObjectID().Hex(),
+			HostID:       userID,
+			Participants: []string{userID},
+			SongID:       playback.SongID,
+			Position:     playback.Position,
+			IsPlaying:    playback.IsPlaying,
+			UpdatedAt:    now,
+			ActiveAt:     now,
+			CreatedAt:    now,
+		}
+		if playback.IsPlaying {
+			session.Position += now.Sub(playback.UpdatedAt).Seconds()
+		}
+
+		for attempt := 0; ; attempt++ {
+			session.InviteCode, err = newInviteCode()
+			if err == nil {
+				err = listenStore.Create(ctx, session)
+			}
+			if err != listenalong.ErrCodeTaken || attempt == 4 {
+				break
+			}
+		}
+		if err != nil {
+			log.Println("❌ Failed to create listen-along session:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{"session": session})
+	}
+}
+
+// JoinListenSession adds the logged-in user to the session with the invite code
+//
+//	POST /listen-along/join {"invite_code": "K7QX2M"}
+func JoinListenSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("user_id")
+
+		var body struct {
+			InviteCode string `json:"invite_code" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invite_code is required"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		session, err := listenStore.FindByCode(ctx, strings.ToUpper(strings.TrimSpace(body.InviteCode)))
+		if err == nil {
+			session, err = listenStore.Update(ctx, session.SessionID, func(session *models.ListenSession) error {
+				if containsString(session.Participants, userID) {
+					return nil
+				}
+				if len(session.Participants) >= listenMaxParticipants {
+					return errSessionFull
+				}
+				session.Participants = append(session.Participants, userID)
+				session.ActiveAt = time.Now()
+				return nil
+			})
+		}
+		switch {
+		case err == listenalong.ErrNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "no session with that invite code"})
+			return
+		case err == errSessionFull:
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "max": listenMaxParticipants})
+			return
+		case err != nil:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to join session"})
+			return
+		}
+
+		listenHub.Broadcast(session.SessionID, listenEvent(models.ListenEventJoined, userID))
+		c.JSON(http.StatusOK, gin.H{"session": session})
+	}
+}
+
+// GetListenSession returns a session the user is part of
+//
+//	GET /listen-along/:session_id
+func GetListenSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		session, ok := findListenSession(ctx, c)
+		if !ok {
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"session": session})
+	}
+}
+
+// LeaveListenSession takes the user out of a session; when the host leaves
+// the session ends for everyone
+//
+//	DELETE /listen-along/:session_id
+func LeaveListenSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("user_id")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		session, ok := findListenSession(ctx, c)
+		if !ok {
+			return
+		}
+
+		if session.HostID == userID {
+			if err := listenStore.Delete(ctx, session.SessionID); err != nil && err != listenalong.ErrNotFound {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to end session"})
+				return
+			}
+			listenHub.Disconnect(session.SessionID, "", listenEvent(models.ListenEventEnded, userID))
+			c.JSON(http.StatusOK, gin.H{"message": "Session ended"})
+			return
+		}
+
+		_, err := listenStore.Update(ctx, session.SessionID, func(session *models.ListenSession) error {
+			participants := session.Participants[:0]
+			for _, participant := range session.Participants {
+				if participant != userID {
+					participants = append(participants, participant)
+				}
+			}
+			session.Participants = participants
+			session.ActiveAt = time.Now()
+			return nil
+		})
+		if err != nil && err != listenalong.ErrNotFound {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to leave session"})
+			return
+		}
+
+		left := listenEvent(models.ListenEventLeft, userID)
+		listenHub.Disconnect(session.SessionID, userID, left)
+		listenHub.Broadcast(session.SessionID, left)
+		c.JSON(http.StatusOK, gin.H{"message": "Left session"})
+	}
+}
+
+// CreateListenTicket issues a single-use ticket for opening the session's
+// WebSocket, which can't carry the Authorization header
+//
+//	POST /listen-along/:session_id/ticket
+func CreateListenTicket() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if _, ok := findListenSession(ctx, c); !ok {
+			return
+		}
+
+		ticket, err := helpers.IssueSocketTicket(c.GetString("user_id"), c.GetString("user_type"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to issue ticket"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"ticket": ticket, "expires_in": int(helpers.SocketTicketTTL.Seconds())})
+	}
+}
+
+// ListenSocket upgrades to a WebSocket carrying the session's playback
+// events. The current state is sent on connect. The host sends play, pause,
+// seek and track events, which everyone receives stamped with server_time;
+// anyone can send ping to get a pong echoing client_time, to work out their
+// clock offset.
+//
+//	GET /listen-along/:session_id/ws?ticket=...
+func ListenSocket() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("user_id")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		session, ok := findListenSession(ctx, c)
+		cancel()
+		if !ok {
+			return
+		}
+
+		conn, err := socketUpgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			log.Println("❌ WebSocket upgrade failed:", err)
+			return
+		}
+
+		listenHub.Serve(session.SessionID, userID, c.GetString("country"), conn, stateEvent(session), func(client *listenalong.Client, event models.ListenEvent) {
+			handleListenEvent(session.SessionID, client, event)
+		})
+	}
+}
+
+// sessionHidesExplicit reports whether anyone in the session hides explicit songs
+func sessionHidesExplicit(ctx context.Context, sessionID string) (bool, error) {
+	session, err := listenStore.Get(ctx, sessionID)
+	if err != nil {
+		return false, err
+	}
+	count, err := usercollection.CountDocuments(ctx, bson.M{
+		"user_id":       bson.M{"$in": session.Participants},
+		"hide_explicit": true,
+	})
+	return count > 0, err
+}
+
+// handleListenEvent applies one message from a connection
+func handleListenEvent(sessionID string, client *listenalong.Client, event models.ListenEvent) {
+	fail := func(message string) {
+		reply := listenEvent(models.ListenEventError, "")
+		reply.Error = message
+		client.Send(reply)
+	}
+
+	switch event.Type {
+	case models.ListenEventPing:
+		pong := listenEvent(models.ListenEventPong, "")
+		pong.ClientTime = event.ClientTime
+		client.Send(pong)
+		return
+	case models.ListenEventPlay, models.ListenEventPause, models.ListenEventSeek, models.ListenEventTrack:
+	default:
+		fail("unknown event type")
+		return
+	}
+	if event.Position < 0 {
+		fail("position can't be negative")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if event.Type == models.ListenEventTrack {
+		if event.SongID == nil {
+			fail("song_id is required")
+			return
+		}
+		// The track plays for everyone, so it has to suit the strictest listener
+		viewer := viewerForUser(ctx, client.UserID)
+		viewer.Country = client.Country
+		if !viewer.HideExplicit {
+			hidden, err := sessionHidesExplicit(ctx, sessionID)
+			if err != nil {
+				fail("failed to check listeners")
+				return
+			}
+			viewer.HideExplicit = hidden
+		}
+		var song models.Song
+		err := songcollection.FindOne(ctx, viewer.songFilter(bson.M{"song_id": *event.SongID}),
+			options.FindOne().SetProjection(bson.M{"song_id": 1, "uploaded_by": 1, "availability": 1}),
+		).Decode(&song)
+		if err != nil {
+			fail("song not found")
+			return
+		}
+		for _, country := range listenHub.Countries(sessionID) {
+			if !(songViewer{Country: country}).availableTo(song) {
+				fail("song isn't available everywhere someone is listening from")
+				return
+			}
+		}
+	}
+
+	session, err := listenStore.Update(ctx, sessionID, func(session *models.ListenSession) error {
+		if session.HostID != client.UserID {
+			return errNotHost
+		}
+		now := time.Now()
+		switch event.Type {
+		case models.ListenEventPlay:
+			session.IsPlaying = true
+		case models.ListenEventPause:
+			session.IsPlaying = false
+		case models.ListenEventTrack:
+			session.SongID = event.SongID
+			session.IsPlaying = true
+		}
+		session.Position = event.Position
+		session.UpdatedAt = now
+		session.ActiveAt = now
+		return nil
+	})
+	if err != nil {
+		if err == errNotHost || err == listenalong.ErrNotFound {
+			fail(err.Error())
+			return
+		}
+		log.Println("❌ Failed to update listen-along session:", err)
+		fail("failed to update session")
+		return
+	}
+
+	broadcast := listenEvent(event.Type, client.UserID)
+	broadcast.SongID = session.SongID
+	broadcast.Position = session.Position
+	broadcast.IsPlaying = session.IsPlaying
+	broadcast.ServerTime = session.UpdatedAt.UnixMilli()
+	listenHub.Broadcast(sessionID, broadcast)
+}
@@ -0,0 +1,315 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ishanbagra18/ecommerce-using-go/database"
+	"github.com/ishanbagra18/ecommerce-using-go/helpers"
+	"github.com/ishanbagra18/ecommerce-using-go/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const maxLyricsBytes = 64 << 10
+
+var lyricsCollection *mongo.Collection
+
+var errInvalidLyrics = errors.New("invalid lyrics")
+
+func InitLyricsController() {
+	lyricsCollection = database.OpenCollection(database.Client, "lyrics")
+	log.Println("✔ Lyrics collection initialized")
+
+	database.CreateIndexes(lyricsCollection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "song_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+	})
+}
+
+// songCreditsFromForm reads the comma-separated writers, producers, composers
+// and labels fields. Only the fields that were sent are returned, keyed by
+// their bson name, so updates can leave the others alone.
+func songCreditsFromForm(c *gin.Context) map[string][]string {
+	fields := map[string][]string{}
+	for _, key := range []string{"writers", "producers", "composers", "labels"} {
+		if _, ok := c.GetPostForm(key); ok {
+			fields[key] = formList(c, key)
+		}
+	}
+	return fields
+}
+
+func songCreditsFromFields(fields map[string][]string) *models.SongCredits {
+	credits := &models.SongCredits{
+		Writers:   fields["writers"],
+		Producers: fields["producers"],
+		Composers: fields["composers"],
+		Labels:    fields["labels"],
+	}
+	if len(credits.Writers)+len(credits.Producers)+len(credits.Composers)+len(credits.Labels) == 0 {
+		return nil
+	}
+	return credits
+}
+
+// buildLyrics validates lyrics text. LRC is parsed into timed lines and must
+// fit inside the song; plain lyrics are stored as they are.
+func buildLyrics(text string, synced bool, duration int) (*models.Lyrics, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, nil
+	}
+	if len(text) > maxLyricsBytes {
+		return nil, fmt.Errorf("%w: lyrics are longer than %d KB", errInvalidLyrics, maxLyricsBytes>>10)
+	}
+
+	if !synced {
+		return &models.Lyrics{Plain: text}, nil
+	}
+
+	parsed, err := helpers.ParseLRC(text)
+	if err != nil {
+		return nil, err
+	}
+	// A few seconds of slack for fades and rounding
+	if last := parsed[len(parsed)-1].TimeMs; duration > 0 && last > int64(duration+5)*1000 {
+		return nil, fmt.Errorf("%w: timestamp %s is past the end of the song", helpers.ErrInvalidLRC, helpers.FormatLRCTime(last))
+	}
+
+	lines := make([]models.LyricLine, 0, len(parsed))
+	for _, line := range parsed {
+		lines = append(lines, models.LyricLine{TimeMs: line.TimeMs, Text: line.Text})
+	}
+	return &models.Lyrics{Plain: helpers.LRCPlainText(parsed), Lines: lines, Synced: true}, nil
+}
+
+// lyricsFromForm reads lyrics sent with an upload: an "lrc" field or an .lrc
+// "lyrics_file" for synced lyrics, or a plain "lyrics" field
+func lyricsFromForm(c *gin.Context, duration int) (*models.Lyrics, error) {
+	if file, header, err := c.Request.FormFile("lyrics_file"); err == nil {
+		defer file.Close()
+		data, err := io.ReadAll(io.LimitReader(file, maxLyricsBytes+1))
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to read lyrics file", errInvalidLyrics)
+		}
+		synced := strings.EqualFold(filepath.Ext(header.Filename), ".lrc")
+		return buildLyrics(string(data), synced, duration)
+	}
+
+	if lrc := c.PostForm("lrc"); strings.TrimSpace(lrc) != "" {
+		return buildLyrics(lrc, true, duration)
+	}
+	return buildLyrics(c.PostForm("lyrics"), false, duration)
+}
+
+// saveLyrics stores a song's lyrics, replacing any it had, and flags the song
+func saveLyrics(ctx context.Context, songID string, lyrics *models.Lyrics, userID string) error {
+	now := time.Now()
+	lyrics.SongID = songID
+	lyrics.UpdatedBy = userID
+	lyrics.UpdatedAt = now
+
+	_, err := lyricsCollection.UpdateOne(ctx,
+		bson.M{"song_id": songID},
+		bson.M{
+			"$set": bson.M{
+				"plain":      lyrics.Plain,
+				"lines":      lyrics.Lines,
+				"synced":     lyrics.Synced,
+				"language":   lyrics.Language,
+				"updated_by": lyrics.UpdatedBy,
+				"updated_at": now,
+			},
+			"$setOnInsert": bson.M{"_id": primitive.NewObjectID(), "created_at": now},
+		},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = songcollection.UpdateOne(ctx,
+		bson.M{"song_id": songID},
+		bson.M{"$set": bson.M{"has_lyrics": true, "has_synced_lyrics": lyrics.Synced}},
+	)
+	return err
+}
+
+// GetSongLyrics returns a song's lyrics as plain text and, when synced, as
+// timed lines the player can highlight as the song plays.
+//
+//	GET /song/:song_id/lyrics
+func GetSongLyrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		songID := c.Param("song_id")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var song models.Song
+		opts := options.FindOne().SetProjection(bson.M{
+			"song_id": 1, "status": 1, "uploaded_by": 1, "is_released": 1, "release_date": 1, "explicit": 1, "availability": 1,
+		})
+		err := songcollection.FindOne(ctx, bson.M{"song_id": songID}, opts).Decode(&song)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				c.JSON(http.StatusNotFound, gin.H{"error": "song not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch song"})
+			return
+		}
+		if !viewerFromContext(c).canView(song) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "song not found"})
+			return
+		}
+
+		var lyrics models.Lyrics
+		err = lyricsCollection.FindOne(ctx, bson.M{"song_id": songID}).Decode(&lyrics)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				c.JSON(http.StatusNotFound, gin.H{"error": "this song has no lyrics"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch lyrics"})
+			return
+		}
+
+		type timedLine struct {
+			TimeMs int64  `json:"time_ms"`
+			Time   string `json:"time"`
+			Text   string `json:"text"`
+		}
+		lines := make([]timedLine, 0, len(lyrics.Lines))
+		for _, line := range lyrics.Lines {
+			lines = append(lines, timedLine{TimeMs: line.TimeMs, Time: helpers.FormatLRCTime(line.TimeMs), Text: line.Text})
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"song_id":    songID,
+			"synced":     lyrics.Synced,
+			"plain":      lyrics.Plain,
+			"lines":      lines,
+			"language":   lyrics.Language,
+			"updated_at": lyrics.UpdatedAt,
+		})
+	}
+}
+
+// SetSongLyrics adds or replaces a song's lyrics. Send "lrc" for synced
+// lyrics or "lyrics" for plain text.
+//
+//	PUT /music/:song_id/lyrics {"lrc": "[00:12.00]First line\n...", "language": "hindi"}
+func SetSongLyrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		songID := c.Param("song_id")
+
+		var body struct {
+			Lyrics   string  `json:"lyrics"`
+			LRC      string  `json:"lrc"`
+			Language *string `json:"language"`
+		}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var song models.Song
+		if err := songcollection.FindOne(ctx, bson.M{"song_id": songID}).Decode(&song); err != nil {
+			if err == mongo.ErrNoDocuments {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Song not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch song"})
+			return
+		}
+		if !canManageSong(c, song) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to edit this song"})
+			return
+		}
+
+		var lyrics *models.Lyrics
+		var err error
+		if strings.TrimSpace(body.LRC) != "" {
+			lyrics, err = buildLyrics(body.LRC, true, song.Duration)
+		} else {
+			lyrics, err = buildLyrics(body.Lyrics, false, song.Duration)
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if lyrics == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "lyrics or lrc is required"})
+			return
+		}
+		lyrics.Language = body.Language
+
+		if err := saveLyrics(ctx, songID, lyrics, c.GetString("user_id")); err != nil {
+			log.Println("❌ Failed to save lyrics:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save lyrics"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Lyrics saved",
+			"synced":  lyrics.Synced,
+			"lines":   len(lyrics.Lines),
+		})
+	}
+}
+
+// DeleteSongLyrics removes a song's lyrics
+//
+//	DELETE /music/:song_id/lyrics
+func DeleteSongLyrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		songID := c.Param("song_id")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var song models.Song
+		if err := songcollection.FindOne(ctx, bson.M{"song_id": songID}).Decode(&song); err != nil {
+			if err == mongo.ErrNoDocuments {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Song not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch song"})
+			return
+		}
+		if !canManageSong(c, song) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to edit this song"})
+			return
+		}
+
+		if _, err := lyricsCollection.DeleteOne(ctx, bson.M{"song_id": songID}); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete lyrics"})
+			return
+		}
+		_, err := songcollection.UpdateOne(ctx,
+			bson.M{"song_id": songID},
+			bson.M{"$unset": bson.M{"has_lyrics": "", "has_synced_lyrics": ""}},
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update song"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Lyrics deleted"})
+	}
+}
@@ -0,0 +1,540 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ishanbagra18/ecommerce-using-go/database"
+	"github.com/ishanbagra18/ecommerce-using-go/helpers"
+	"github.com/ishanbagra18/ecommerce-using-go/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// migration is a one-off data fix that runs once on startup
+type migration struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// Append new migrations at the end, never reorder or rename existing ones
+var migrations = []migration{
+	{name: "songs_default_status_approved", run: backfillSongStatus},
+	{name: "songs_backfill_is_released", run: backfillSongReleased},
+	{name: "songs_backfill_reaction_counts", run: backfillReactionCounts},
+	{name: "history_mark_counted", run: backfillHistoryCounted},
+	{name: "albums_from_song_album_strings", run: migrateAlbumStrings},
+	{name: "songs_link_artist_ids", run: linkSongArtists},
+	{name: "genres_from_song_strings", run: migrateGenreStrings},
+}
+
+var migrationCollection *mongo.Collection
+
+// RunMigrations applies every migration that hasn't been recorded yet.
+// Call it after all controllers are initialized.
+func RunMigrations() {
+	migrationCollection = database.OpenCollection(database.Client, "migrations")
+
+	for _, m := range migrations {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+
+		count, err := migrationCollection.CountDocuments(ctx, bson.M{"name": m.name})
+		if err != nil {
+			log.Printf("❌ [RunMigrations] Failed to check migration %s: %v\n", m.name, err)
+			cancel()
+			continue
+		}
+		if count > 0 {
+			cancel()
+			continue
+		}
+
+		log.Printf("🔍 [RunMigrations] Running migration %s\n", m.name)
+		if err := m.run(ctx); err != nil {
+			log.Printf("❌ [RunMigrations] Migration %s failed: %v\n", m.name, err)
+			cancel()
+			continue
+		}
+
+		_, err = migrationCollection.InsertOne(ctx, bson.M{"name": m.name, "applied_at": time.Now()})
+		if err != nil {
+			log.Printf("⚠️ [RunMigrations] Migration %s ran but was not recorded: %v\n", m.name, err)
+		}
+		log.Printf("✅ [RunMigrations] Migration %s applied\n", m.name)
+		cancel()
+	}
+}
+
+// Songs uploaded before moderation existed were already live
+func backfillSongStatus(ctx context.Context) error {
+	result, err := songcollection.UpdateMany(ctx,
+		bson.M{"status": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"status": models.SongStatusApproved}},
+	)
+	if err != nil {
+		return err
+	}
+	log.Printf("🔍 [backfillSongStatus] %d songs marked approved\n", result.ModifiedCount)
+	return nil
+}
+
+// Songs uploaded with a future release_date before embargoes existed are
+// hidden until then; everything else is already out
+func backfillSongReleased(ctx context.Context) error {
+	now := time.Now()
+
+	embargoed, err := songcollection.UpdateMany(ctx,
+		bson.M{"is_released": bson.M{"$exists": false}, "release_date": bson.M{"$gt": now}},
+		bson.M{"$set": bson.M{"is_released": false, "release_notified": false}},
+	)
+	if err != nil {
+		return err
+	}
+
+	released, err := songcollection.UpdateMany(ctx,
+		bson.M{"is_released": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"is_released": true}},
+	)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("🔍 [backfillSongReleased] %d songs embargoed, %d marked released\n", embargoed.ModifiedCount, released.ModifiedCount)
+	return nil
+}
+
+// like_count/save_count start from the existing arrays. Old likes have no
+// timestamp, so weekly and monthly rankings only count reactions from now on.
+func backfillReactionCounts(ctx context.Context) error {
+	result, err := recountReactions(ctx, bson.M{})
+	if err != nil {
+		return err
+	}
+	log.Printf("🔍 [backfillReactionCounts] %d songs recounted\n", result.ModifiedCount)
+	return nil
+}
+
+// History rows written before play sessions existed were already counted in play_count
+func backfillHistoryCounted(ctx context.Context) error {
+	result, err := historyCollection.UpdateMany(ctx,
+		bson.M{"counted": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"counted": true, "ended": true}},
+	)
+	if err != nil {
+		return err
+	}
+	log.Printf("🔍 [backfillHistoryCounted] %d history rows marked counted\n", result.ModifiedCount)
+	return nil
+}
+
+// Songs only had a free-text album name before albums existed. Songs sharing
+// an album name and artist become one album, with tracks in upload order.
+func migrateAlbumStrings(ctx context.Context) error {
+	cursor, err := songcollection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"album_id": bson.M{"$exists": false}, "album": bson.M{"$nin": bson.A{nil, ""}}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "created_at", Value: 1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"album":  bson.M{"$toLower": bson.M{"$trim": bson.M{"input": "$album"}}},
+				"artist": bson.M{"$toLower": bson.M{"$trim": bson.M{"input": bson.M{"$ifNull": bson.A{"$artist", ""}}}}},
+			},
+			"title":        bson.M{"$first": "$album"},
+			"artist":       bson.M{"$first": "$artist"},
+			"cover_url":    bson.M{"$first": "$image_url"},
+			"uploaded_by":  bson.M{"$first": "$uploaded_by"},
+			"release_date": bson.M{"$min": "$release_date"},
+			"song_ids":     bson.M{"$push": "$song_id"},
+		}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	var groups []struct {
+		Title       string     `bson:"title"`
+		Artist      *string    `bson:"artist"`
+		CoverURL    *string    `bson:"cover_url"`
+		UploadedBy  string     `bson:"uploaded_by"`
+		ReleaseDate *time.Time `bson:"release_date"`
+		SongIDs     []string   `bson:"song_ids"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, g := range groups {
+		title := strings.TrimSpace(g.Title)
+
+		albumType := models.AlbumTypeAlbum
+		switch {
+		case len(g.SongIDs) == 1:
+			albumType = models.AlbumTypeSingle
+		case len(g.SongIDs) <= 6:
+			albumType = models.AlbumTypeEP
+		}
+
+		tracks := make([]models.AlbumTrack, 0, len(g.SongIDs))
+		for i, songID := range g.SongIDs {
+			tracks = append(tracks, models.AlbumTrack{SongID: songID, DiscNumber: 1, TrackNumber: i + 1})
+		}
+
+		album := models.Album{
+			ID:          primitive.NewObjectID(),
+			Title:       &title,
+			Artist:      g.Artist,
+			Type:        albumType,
+			ReleaseDate: g.ReleaseDate,
+			CoverURL:    g.CoverURL,
+			Tracks:      tracks,
+			CreatedBy:   g.UploadedBy,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		album.AlbumID = album.ID.Hex()
+
+		if _, err := albumCollection.InsertOne(ctx, album); err != nil {
+			return err
+		}
+		if _, err := songcollection.UpdateMany(ctx,
+			bson.M{"song_id": bson.M{"$in": g.SongIDs}},
+			bson.M{"$set": bson.M{"album_id": album.AlbumID, "album": title}},
+		); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("🔍 [migrateAlbumStrings] %d albums created from song album names\n", len(groups))
+	return nil
+}
+
+// linkSongArtists credits the artists named on a song, creating artist
+// profiles for names that don't have one, then gives albums the artists of
+// their tracks. Artists whose names differ only in case are merged first so
+// the names can be unique.
+func linkSongArtists(ctx context.Context) error {
+	if err := mergeDuplicateArtists(ctx); err != nil {
+		return err
+	}
+
+	cursor, err := songcollection.Find(ctx,
+		bson.M{"artist_ids": bson.M{"$exists": false}},
+		options.Find().SetProjection(bson.M{"song_id": 1, "artist": 1, "artists": 1}),
+	)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	linked := 0
+	for cursor.Next(ctx) {
+		var song models.Song
+		if err := cursor.Decode(&song); err != nil {
+			return err
+		}
+
+		var inputs []creditInput
+		for _, name := range song.Artists {
+			inputs = append(inputs, creditInput{Name: name, Role: models.ArtistRolePrimary})
+		}
+		if song.Artist != nil {
+			fromLine, err := creditsFromArtistString(ctx, *song.Artist)
+			if err != nil {
+				return err
+			}
+			inputs = append(inputs, fromLine...)
+		}
+
+		var deduped []creditInput
+		seen := make(map[string]bool)
+		for _, input := range inputs {
+			input.Name = strings.TrimSpace(input.Name)
+			if input.Name == "" || seen[strings.ToLower(input.Name)] {
+				continue
+			}
+			seen[strings.ToLower(input.Name)] = true
+			deduped = append(deduped, input)
+		}
+		inputs = deduped
+
+		credits, artistIDs, err := resolveArtistCredits(ctx, inputs)
+		if err != nil {
+			return err
+		}
+		if _, err := songcollection.UpdateOne(ctx,
+			bson.M{"song_id": song.SongID},
+			bson.M{"$set": bson.M{"artist_ids": artistIDs, "artist_credits": credits}},
+		); err != nil {
+			return err
+		}
+		linked++
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+
+	albumCursor, err := albumCollection.Find(ctx, bson.M{"artist_ids": bson.M{"$exists": false}})
+	if err != nil {
+		return err
+	}
+	var albums []models.Album
+	if err := albumCursor.All(ctx, &albums); err != nil {
+		return err
+	}
+	for _, album := range albums {
+		if err := linkAlbumArtists(ctx, album.AlbumID); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("🔍 [linkSongArtists] %d songs linked to artists\n", linked)
+	return nil
+}
+
+// linkAlbumArtists sets an album's artists to the primary artists of its tracks
+func linkAlbumArtists(ctx context.Context, albumID string) error {
+	trackCursor, err := songcollection.Find(ctx,
+		bson.M{"album_id": albumID},
+		options.Find().SetProjection(bson.M{"artist_credits": 1}),
+	)
+	if err != nil {
+		return err
+	}
+	var tracks []models.Song
+	if err := trackCursor.All(ctx, &tracks); err != nil {
+		return err
+	}
+
+	var artistIDs []string
+	for _, track := range tracks {
+		for _, credit := range track.ArtistCredits {
+			if credit.Role == models.ArtistRolePrimary && !containsString(artistIDs, credit.ArtistID) {
+				artistIDs = append(artistIDs, credit.ArtistID)
+			}
+		}
+	}
+	if len(artistIDs) == 0 {
+		return nil
+	}
+	_, err = albumCollection.UpdateOne(ctx,
+		bson.M{"album_id": albumID},
+		bson.M{"$set": bson.M{"artist_ids": artistIDs}},
+	)
+	return err
+}
+
+// migrateGenreStrings seeds the moods and builds the genre taxonomy from the
+// free-text genres on songs and artists, pointing each song at its genre
+func migrateGenreStrings(ctx context.Context) error {
+	now := time.Now()
+	for _, name := range defaultMoods {
+		_, err := genreCollection.UpdateOne(ctx,
+			bson.M{"slug": helpers.Slugify(name)},
+			bson.M{"$setOnInsert": models.Genre{
+				ID:        primitive.NewObjectID(),
+				Slug:      helpers.Slugify(name),
+				Name:      name,
+				Kind:      models.GenreKindMood,
+				CreatedAt: now,
+				UpdatedAt: now,
+			}},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	values, err := songcollection.Distinct(ctx, "genre", bson.M{"genre_slug": bson.M{"$exists": false}})
+	if err != nil {
+		return err
+	}
+	songs := 0
+	for _, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			continue
+		}
+		genre, err := findOrCreateGenre(ctx, raw)
+		if err != nil {
+			return err
+		}
+
+		set := bson.M{"genre": ""}
+		if genre != nil {
+			set = bson.M{"genre": genre.Name, "genre_slug": genre.Slug}
+		}
+		result, err := songcollection.UpdateMany(ctx,
+			bson.M{"genre": raw, "genre_slug": bson.M{"$exists": false}},
+			bson.M{"$set": set},
+		)
+		if err != nil {
+			return err
+		}
+		songs += int(result.ModifiedCount)
+	}
+
+	cursor, err := artistCollection.Find(ctx,
+		bson.M{"genre.0": bson.M{"$exists": true}},
+		options.Find().SetProjection(bson.M{"artist_id": 1, "genre": 1}),
+	)
+	if err != nil {
+		return err
+	}
+	var artists []models.Artist
+	if err := cursor.All(ctx, &artists); err != nil {
+		return err
+	}
+	for _, artist := range artists {
+		genres := []string{}
+		for _, name := range artist.Genre {
+			genre, err := findOrCreateGenre(ctx, name)
+			if err != nil {
+				return err
+			}
+			if genre != nil && !containsString(genres, genre.Name) {
+				genres = append(genres, genre.Name)
+			}
+		}
+		if _, err := artistCollection.UpdateOne(ctx,
+			bson.M{"artist_id": artist.Artist_id},
+			bson.M{"$set": bson.M{"genre": genres}},
+		); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("🔍 [migrateGenreStrings] %d songs and %d artists mapped to the genre taxonomy\n", songs, len(artists))
+	return nil
+}
+
+// mergeDuplicateArtists folds artists whose names differ only in case into
+// the oldest of them, moving their songs, albums, followers and radio
+// stations over, then creates the unique name index
+func mergeDuplicateArtists(ctx context.Context) error {
+	cursor, err := artistCollection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$sort", Value: bson.M{"created_at": 1}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":        "$name",
+			"artist_ids": bson.M{"$push": "$artist_id"},
+		}}},
+		{{Key: "$match", Value: bson.M{"artist_ids.1": bson.M{"$exists": true}}}},
+	}, options.Aggregate().SetCollation(artistNameCollation))
+	if err != nil {
+		return err
+	}
+	var groups []struct {
+		ArtistIDs []string `bson:"artist_ids"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return err
+	}
+
+	userCollection := database.GetCollection("ecommerce", "users")
+	merged := 0
+	for _, g := range groups {
+		keep := g.ArtistIDs[0]
+		for _, dup := range g.ArtistIDs[1:] {
+			if err := mergeArtistInto(ctx, userCollection, dup, keep); err != nil {
+				return err
+			}
+			merged++
+		}
+
+		var followers struct {
+			Followers []string `bson:"followers"`
+		}
+		if err := artistCollection.FindOne(ctx, bson.M{"artist_id": keep}).Decode(&followers); err != nil {
+			return err
+		}
+		if _, err := artistCollection.UpdateOne(ctx,
+			bson.M{"artist_id": keep},
+			bson.M{"$set": bson.M{"follower_count": len(followers.Followers)}},
+		); err != nil {
+			return err
+		}
+	}
+
+	if _, err := artistCollection.Indexes().CreateOne(ctx, artistNameIndex); err != nil {
+		return err
+	}
+
+	log.Printf("🔍 [mergeDuplicateArtists] %d duplicate artists merged\n", merged)
+	return nil
+}
+
+// mergeArtistInto points everything that references artist "from" at artist
+// "to" and deletes "from"
+func mergeArtistInto(ctx context.Context, userCollection *mongo.Collection, from string, to string) error {
+	var dup models.Artist
+	if err := artistCollection.FindOne(ctx, bson.M{"artist_id": from}).Decode(&dup); err != nil {
+		return err
+	}
+
+	// Songs credited to both keep one credit; the rest are repointed
+	if _, err := songcollection.UpdateMany(ctx,
+		bson.M{"artist_ids": bson.M{"$all": []string{from, to}}},
+		bson.M{"$pull": bson.M{"artist_ids": from, "artist_credits": bson.M{"artist_id": from}}},
+	); err != nil {
+		return err
+	}
+	if _, err := songcollection.UpdateMany(ctx,
+		bson.M{"artist_ids": from},
+		bson.M{"$set": bson.M{"artist_ids.$[a]": to, "artist_credits.$[c].artist_id": to}},
+		options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{
+			bson.M{"a": from},
+			bson.M{"c.artist_id": from},
+		}}),
+	); err != nil {
+		return err
+	}
+
+	if _, err := albumCollection.UpdateMany(ctx,
+		bson.M{"artist_ids": bson.M{"$all": []string{from, to}}},
+		bson.M{"$pull": bson.M{"artist_ids": from}},
+	); err != nil {
+		return err
+	}
+	if _, err := albumCollection.UpdateMany(ctx,
+		bson.M{"artist_ids": from},
+		bson.M{"$set": bson.M{"artist_ids.$": to}},
+	); err != nil {
+		return err
+	}
+
+	if _, err := userCollection.UpdateMany(ctx,
+		bson.M{"followed_artists": from},
+		bson.M{"$addToSet": bson.M{"followed_artists": to}},
+	); err != nil {
+		return err
+	}
+	if _, err := userCollection.UpdateMany(ctx,
+		bson.M{"followed_artists": from},
+		bson.M{"$pull": bson.M{"followed_artists": from}},
+	); err != nil {
+		return err
+	}
+	if len(dup.Followers) > 0 {
+		if _, err := artistCollection.UpdateOne(ctx,
+			bson.M{"artist_id": to},
+			bson.M{"$addToSet": bson.M{"followers": bson.M{"$each": dup.Followers}}},
+		); err != nil {
+			return err
+		}
+	}
+
+	if _, err := radioCollection.UpdateMany(ctx,
+		bson.M{"seed_type": models.RadioSeedArtist, "seed_id": from},
+		bson.M{"$set": bson.M{"seed_id": to}},
+	); err != nil {
+		return err
+	}
+
+	_, err := artistCollection.DeleteOne(ctx, bson.M{"artist_id": from})
+	return err
+}
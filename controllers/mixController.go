@@ -0,0 +1,439 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ishanbagra18/ecommerce-using-go/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	mixSize            = 30
+	mixDailyCount      = 3
+	mixDailyMinSongs   = 3                   // a genre needs this many of the user's songs to get a Daily Mix
+	mixActiveWindow    = 30 * 24 * time.Hour // only listeners active this recently get mixes
+	releaseRadarWindow = 14 * 24 * time.Hour
+	onRepeatWindow     = 30 * 24 * time.Hour
+)
+
+const (
+	mixKeyDiscoverWeekly = "discover_weekly"
+	mixKeyReleaseRadar   = "release_radar"
+	mixKeyOnRepeat       = "on_repeat"
+)
+
+func dailyMixKey(n int) string {
+	return "daily_mix_" + strconv.Itoa(n)
+}
+
+// mixRefresh is how old a mix may get before the job rebuilds it
+func mixRefresh(key string) time.Duration {
+	if key == mixKeyDiscoverWeekly || key == mixKeyReleaseRadar {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// StartMixJob checks every hour for personal mixes that are due a refresh
+func StartMixJob() {
+	startPeriodicJob("personal-mixes", time.Hour, 30*time.Minute, generateMixes)
+}
+
+// generateMixes rebuilds the due mixes of everyone who listened in the last
+// mixActiveWindow, in place, and drops mixes of listeners who went quiet
+func generateMixes(ctx context.Context) error {
+	now := time.Now()
+
+	users, err := historyCollection.Distinct(ctx, "user_id", bson.M{
+		"counted":   true,
+		"played_at": bson.M{"$gte": now.Add(-mixActiveWindow)},
+	})
+	if err != nil {
+		return err
+	}
+
+	built := 0
+	for _, user := range users {
+		userID, ok := user.(string)
+		if !ok {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n, err := refreshUserMixes(ctx, userID, now)
+		if err != nil {
+			log.Printf("❌ Failed to build mixes for %s: %v\n", userID, err)
+			continue
+		}
+		built += n
+	}
+
+	// Mixes nobody refreshed for a whole window belong to inactive listeners
+	result, err := playlistCollection.DeleteMany(ctx, bson.M{
+		"mix_key":    bson.M{"$exists": true},
+		"updated_at": bson.M{"$lt": now.Add(-mixActiveWindow)},
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("✔ Personal mixes: %d rebuilt for %d listeners, %d expired\n", built, len(users), result.DeletedCount)
+	return nil
+}
+
+// refreshUserMixes rebuilds whichever of a user's mixes are due and returns
+// how many it rebuilt
+func refreshUserMixes(ctx context.Context, userID string, now time.Time) (int, error) {
+	cursor, err := playlistCollection.Find(ctx,
+		bson.M{"owner_id": userID, "mix_key": bson.M{"$exists": true}},
+		options.Find().SetProjection(bson.M{"mix_key": 1, "updated_at": 1}),
+	)
+	if err != nil {
+		return 0, err
+	}
+	var existing []models.Playlist
+	if err := cursor.All(ctx, &existing); err != nil {
+		return 0, err
+	}
+	refreshedAt := make(map[string]time.Time, len(existing))
+	for _, mix := range existing {
+		refreshedAt[*mix.MixKey] = mix.UpdatedAt
+	}
+	due := func(key string) bool {
+		at, ok := refreshedAt[key]
+		return !ok || now.Sub(at) >= mixRefresh(key)
+	}
+
+	viewer := viewerForUser(ctx, userID)
+	built := 0
+
+	// Daily mixes are rebuilt together since the clusters shift between runs
+	if due(dailyMixKey(1)) {
+		mixes, err := dailyMixes(ctx, viewer)
+		if err != nil {
+			return built, err
+		}
+		for i := 0; i < mixDailyCount; i++ {
+			var mix generatedMix
+			if i < len(mixes) {
+				mix = mixes[i]
+			}
+			if err := saveMix(ctx, userID, dailyMixKey(i+1), mix, now); err != nil {
+				return built, err
+			}
+		}
+		built += len(mixes)
+	}
+
+	builders := []struct {
+		key   string
+		build func(context.Context, songViewer) (generatedMix, error)
+	}{
+		{mixKeyDiscoverWeekly, discoverWeekly},
+		{mixKeyReleaseRadar, releaseRadar},
+		{mixKeyOnRepeat, onRepeat},
+	}
+	for _, builder := range builders {
+		if !due(builder.key) {
+			continue
+		}
+		mix, err := builder.build(ctx, viewer)
+		if err != nil {
+			return built, err
+		}
+		if err := saveMix(ctx, userID, builder.key, mix, now); err != nil {
+			return built, err
+		}
+		if len(mix.SongIDs) > 0 {
+			built++
+		}
+	}
+
+	return built, nil
+}
+
+type generatedMix struct {
+	Name        string
+	Description string
+	SongIDs     []string
+}
+
+// saveMix replaces the songs of a user's mix in place, keeping its ID so
+// links to it stay valid. A mix with no songs is removed.
+func saveMix(ctx context.Context, userID string, key string, mix generatedMix, now time.Time) error {
+	filter := bson.M{"owner_id": userID, "mix_key": key}
+
+	if len(mix.SongIDs) == 0 {
+		_, err := playlistCollection.DeleteOne(ctx, filter)
+		return err
+	}
+
+	_, err := playlistCollection.UpdateOne(ctx, filter, bson.M{
+		"$set": bson.M{
+			"name":        mix.Name,
+			"description": mix.Description,
+			"song_ids":    mix.SongIDs,
+			"updated_at":  now,
+		},
+		"$setOnInsert": bson.M{
+			"type":       models.PlaylistTypeSystem,
+			"is_public":  false,
+			"is_seeded":  true,
+			"play_count": 0,
+			"created_at": now,
+		},
+	}, options.Update().SetUpsert(true))
+	return err
+}
+
+// dailyMixes clusters the user's favourites by genre and builds one mix per
+// top genre, alternating songs they love with ones in that genre they haven't
+// played yet
+func dailyMixes(ctx context.Context, viewer songViewer) ([]generatedMix, error) {
+	profile, err := buildListenerProfile(ctx, viewer.UserID)
+	if err != nil {
+		return nil, err
+	}
+	favourites := topKeys(profile.affinity, profileSeeds)
+	if len(favourites) == 0 {
+		return nil, nil
+	}
+
+	cursor, err := songcollection.Find(ctx,
+		viewer.songFilter(bson.M{"song_id": bson.M{"$in": favourites}, "genre_slug": bson.M{"$exists": true}}),
+		options.Find().SetProjection(bson.M{"song_id": 1, "genre": 1, "genre_slug": 1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var songs []models.Song
+	if err := cursor.All(ctx, &songs); err != nil {
+		return nil, err
+	}
+
+	weights := make(map[string]float64)
+	members := make(map[string]map[string]float64)
+	names := make(map[string]string)
+	for _, song := range songs {
+		slug := *song.GenreSlug
+		weights[slug] += profile.affinity[song.SongID]
+		if members[slug] == nil {
+			members[slug] = make(map[string]float64)
+		}
+		members[slug][song.SongID] = profile.affinity[song.SongID]
+		if song.Genre != nil {
+			names[slug] = *song.Genre
+		}
+	}
+
+	exclude := make(map[string]bool, len(profile.affinity))
+	for songID := range profile.affinity {
+		exclude[songID] = true
+	}
+
+	var mixes []generatedMix
+	for _, slug := range topKeys(weights, len(weights)) {
+		if len(mixes) == mixDailyCount {
+			break
+		}
+		if len(members[slug]) < mixDailyMinSongs {
+			continue
+		}
+
+		familiar := topKeys(members[slug], mixSize/2)
+		slugs, err := genreSlugsWithDescendants(ctx, []string{slug})
+		if err != nil {
+			return nil, err
+		}
+		fresh, err := popularSongs(ctx, viewer, bson.M{"genre_slug": bson.M{"$in": slugs}}, exclude, mixSize-len(familiar), "")
+		if err != nil {
+			return nil, err
+		}
+
+		songIDs := make([]string, 0, mixSize)
+		for i := 0; i < len(familiar) || i < len(fresh); i++ {
+			if i < len(familiar) {
+				songIDs = append(songIDs, familiar[i])
+			}
+			if i < len(fresh) {
+				songIDs = append(songIDs, fresh[i].SongID)
+			}
+		}
+
+		name := names[slug]
+		if name == "" {
+			name = slug
+		}
+		mixes = append(mixes, generatedMix{
+			Name:        "Daily Mix " + strconv.Itoa(len(mixes)+1),
+			Description: name + " you love, and more like it you haven't heard yet",
+			SongIDs:     songIDs,
+		})
+	}
+
+	return mixes, nil
+}
+
+// discoverWeekly is the user's recommendations, which never include songs
+// they already play or like
+func discoverWeekly(ctx context.Context, viewer songViewer) (generatedMix, error) {
+	songs, err := recommendSongs(ctx, viewer, mixSize)
+	if err != nil {
+		return generatedMix{}, err
+	}
+
+	songIDs := make([]string, 0, len(songs))
+	for _, song := range songs {
+		songIDs = append(songIDs, song.SongID)
+	}
+	return generatedMix{
+		Name:        "Discover Weekly",
+		Description: "Songs picked for you that you haven't heard yet. Refreshed every week.",
+		SongIDs:     songIDs,
+	}, nil
+}
+
+// releaseRadar collects recent releases from the artists the user follows
+func releaseRadar(ctx context.Context, viewer songViewer) (generatedMix, error) {
+	var user models.User
+	err := usercollection.FindOne(ctx, bson.M{"user_id": viewer.UserID},
+		options.FindOne().SetProjection(bson.M{"followed_artists": 1}),
+	).Decode(&user)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return generatedMix{}, err
+	}
+	if len(user.FollowedArtists) == 0 {
+		return generatedMix{}, nil
+	}
+
+	since := time.Now().Add(-releaseRadarWindow)
+	cursor, err := songcollection.Find(ctx,
+		viewer.songFilter(bson.M{
+			"artist_ids": bson.M{"$in": user.FollowedArtists},
+			"$or": bson.A{
+				bson.M{"release_date": bson.M{"$gte": since}},
+				bson.M{"release_date": nil, "created_at": bson.M{"$gte": since}},
+			},
+		}),
+		options.Find().
+			SetSort(bson.D{{Key: "release_date", Value: -1}, {Key: "created_at", Value: -1}}).
+			SetLimit(mixSize).
+			SetProjection(bson.M{"song_id": 1}),
+	)
+	if err != nil {
+		return generatedMix{}, err
+	}
+	var songs []models.Song
+	if err := cursor.All(ctx, &songs); err != nil {
+		return generatedMix{}, err
+	}
+
+	songIDs := make([]string, 0, len(songs))
+	for _, song := range songs {
+		songIDs = append(songIDs, song.SongID)
+	}
+	return generatedMix{
+		Name:        "Release Radar",
+		Description: "New music from the artists you follow",
+		SongIDs:     songIDs,
+	}, nil
+}
+
+// onRepeat is what the user keeps coming back to lately
+func onRepeat(ctx context.Context, viewer songViewer) (generatedMix, error) {
+	cursor, err := historyCollection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"user_id":   viewer.UserID,
+			"counted":   true,
+			"played_at": bson.M{"$gte": time.Now().Add(-onRepeatWindow)},
+		}}},
+		{{Key: "$group", Value: bson.M{"_id": "$song_id", "plays": bson.M{"$sum": 1}}}},
+		{{Key: "$match", Value: bson.M{"plays": bson.M{"$gte": 2}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "plays", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: mixSize * 2}},
+	})
+	if err != nil {
+		return generatedMix{}, err
+	}
+	var counts []struct {
+		SongID string `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &counts); err != nil {
+		return generatedMix{}, err
+	}
+	if len(counts) == 0 {
+		return generatedMix{}, nil
+	}
+
+	ranked := make([]string, 0, len(counts))
+	for _, count := range counts {
+		ranked = append(ranked, count.SongID)
+	}
+	cursor, err = songcollection.Find(ctx,
+		viewer.songFilter(bson.M{"song_id": bson.M{"$in": ranked}}),
+		options.Find().SetProjection(bson.M{"song_id": 1}),
+	)
+	if err != nil {
+		return generatedMix{}, err
+	}
+	var songs []models.Song
+	if err := cursor.All(ctx, &songs); err != nil {
+		return generatedMix{}, err
+	}
+	visible := make(map[string]bool, len(songs))
+	for _, song := range songs {
+		visible[song.SongID] = true
+	}
+
+	songIDs := make([]string, 0, mixSize)
+	for _, songID := range ranked {
+		if visible[songID] && len(songIDs) < mixSize {
+			songIDs = append(songIDs, songID)
+		}
+	}
+	return generatedMix{
+		Name:        "On Repeat",
+		Description: "The songs you've been playing the most this month",
+		SongIDs:     songIDs,
+	}, nil
+}
+
+// GetMyMixes lists the mixes generated for the logged-in user
+//
+//	GET /playlist/mixes
+func GetMyMixes() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		cursor, err := playlistCollection.Find(ctx,
+			bson.M{"owner_id": c.GetString("user_id"), "mix_key": bson.M{"$exists": true}},
+			options.Find().SetSort(bson.D{{Key: "mix_key", Value: 1}}),
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching mixes"})
+			return
+		}
+		mixes := []models.Playlist{}
+		if err := cursor.All(ctx, &mixes); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching mixes"})
+			return
+		}
+		// Mixes were built with the preferences of their last refresh
+		if err := viewPlaylists(ctx, viewerFromContext(c), mixes); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching mixes"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"mixes": mixes, "count": len(mixes)})
+	}
+}
@@ -0,0 +1,185 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ishanbagra18/ecommerce-using-go/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetModerationQueue lists uploads waiting for review, oldest first (Admin only)
+func GetModerationQueue() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log.Println("🔹 GetModerationQueue endpoint hit")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		status := c.DefaultQuery("status", models.SongStatusPending)
+		if !isValidSongStatus(status) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status, use one of: pending | approved | rejected | taken_down"})
+			return
+		}
+
+		opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+		cursor, err := songcollection.Find(ctx, bson.M{"status": status}, opts)
+		if err != nil {
+			log.Println("❌ Failed to fetch moderation queue:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch moderation queue"})
+			return
+		}
+		defer cursor.Close(ctx)
+
+		var songs []models.Song
+		if err := cursor.All(ctx, &songs); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse songs"})
+			return
+		}
+
+		if songs == nil {
+			songs = []models.Song{}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"status": status,
+			"songs":  songs,
+			"count":  len(songs),
+		})
+	}
+}
+
+// ApproveSong makes a pending (or previously rejected) song live (Admin only)
+func ApproveSong() gin.HandlerFunc {
+	return reviewSong(models.SongStatusApproved, false)
+}
+
+// RejectSong keeps a song out of public listings and records why (Admin only)
+func RejectSong() gin.HandlerFunc {
+	return reviewSong(models.SongStatusRejected, true)
+}
+
+// TakeDownSong removes an already live song from public listings (Admin only)
+func TakeDownSong() gin.HandlerFunc {
+	return reviewSong(models.SongStatusTakenDown, true)
+}
+
+// reviewSong builds the handler shared by approve/reject/takedown. Moderators
+// can set or clear the explicit flag while reviewing.
+func reviewSong(status string, reasonRequired bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		songID := c.Param("song_id")
+
+		var body struct {
+			Reason   string `json:"reason"`
+			Explicit *bool  `json:"explicit"`
+		}
+		// Body is optional for approve, but what is sent must be valid
+		if err := c.ShouldBindJSON(&body); err != nil && !errors.Is(err, io.EOF) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body, explicit must be true or false"})
+			return
+		}
+
+		reason := strings.TrimSpace(body.Reason)
+		if reasonRequired && reason == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "reason is required"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		now := time.Now()
+		set := bson.M{
+			"status":      status,
+			"reviewed_by": c.GetString("user_id"),
+			"reviewed_at": now,
+			"updated_at":  now,
+		}
+		if body.Explicit != nil {
+			set["explicit"] = *body.Explicit
+		}
+		update := bson.M{"$set": set}
+		if reason != "" {
+			set["moderation_reason"] = reason
+		} else {
+			update["$unset"] = bson.M{"moderation_reason": ""}
+		}
+
+		var song models.Song
+		err := songcollection.FindOneAndUpdate(ctx,
+			bson.M{"song_id": songID},
+			update,
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&song)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Song not found"})
+				return
+			}
+			log.Println("❌ Failed to update song status:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update song status"})
+			return
+		}
+
+		log.Printf("✅ Song %s marked %s by %s\n", songID, status, c.GetString("user_id"))
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Song status updated",
+			"song":    song,
+		})
+	}
+}
+
+// SetTrustedUploader turns auto-approval on or off for a user (Admin only)
+func SetTrustedUploader() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.Param("user_id")
+
+		var body struct {
+			Trusted *bool `json:"trusted" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "trusted (true/false) is required"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		result, err := usercollection.UpdateOne(ctx,
+			bson.M{"user_id": userID},
+			bson.M{"$set": bson.M{"trusted_uploader": *body.Trusted, "updated_at": time.Now()}},
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
+			return
+		}
+
+		if result.MatchedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":          "Trusted uploader flag updated",
+			"trusted_uploader": *body.Trusted,
+		})
+	}
+}
+
+func isValidSongStatus(status string) bool {
+	switch status {
+	case models.SongStatusPending, models.SongStatusApproved, models.SongStatusRejected, models.SongStatusTakenDown:
+		return true
+	}
+	return false
+}
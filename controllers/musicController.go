@@ -2,9 +2,11 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"log"
 	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -24,6 +26,36 @@ var songcollection *mongo.Collection
 func InitMusicController() {
 	songcollection = database.OpenCollection(database.Client, "songs")
 	log.Println("✔ Music collection initialized")
+
+	reactionCollection = database.OpenCollection(database.Client, "song_reactions")
+	database.CreateIndexes(reactionCollection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "song_id", Value: 1}, {Key: "user_id", Value: 1}, {Key: "type", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "type", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+
+	database.CreateIndexes(songcollection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "song_id", Value: 1}}},
+		{Keys: bson.D{{Key: "content_hash", Value: 1}}},
+		{Keys: bson.D{{Key: "fingerprint", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "is_released", Value: 1}, {Key: "release_date", Value: 1}}},
+		{Keys: bson.D{{Key: "album", Value: 1}}},
+		{Keys: bson.D{{Key: "artist_ids", Value: 1}}},
+		{Keys: bson.D{{Key: "genre", Value: 1}}},
+		{Keys: bson.D{{Key: "genre_slug", Value: 1}}},
+		{Keys: bson.D{{Key: "moods", Value: 1}}},
+		{Keys: bson.D{{Key: "language", Value: 1}}},
+		{Keys: bson.D{{Key: "play_count", Value: -1}}},
+		{Keys: bson.D{{Key: "like_count", Value: -1}}},
+		{Keys: bson.D{{Key: "save_count", Value: -1}}},
+		{
+			// "none" keeps Hindi/Punjabi words intact instead of applying English stemming
+			Keys: bson.D{{Key: "title", Value: "text"}, {Key: "artist", Value: "text"}, {Key: "album", Value: "text"}, {Key: "genre", Value: "text"}, {Key: "info", Value: "text"}},
+			Options: options.Index().
+				SetName("song_text").
+				SetDefaultLanguage("none").
+				SetWeights(bson.D{{Key: "title", Value: 10}, {Key: "artist", Value: 6}, {Key: "album", Value: 4}, {Key: "genre", Value: 2}, {Key: "info", Value: 1}}),
+		},
+	})
 }
 
 // controllers/music.go
@@ -44,72 +76,13 @@ func GetSongByID() gin.HandlerFunc {
 			return
 		}
 
-		// 2️⃣ If user is logged in → history + play count logic
-		userID, exists := c.Get("user_id")
-		if exists {
-
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			defer cancel()
-
-			// 🔒 Prevent fake play count (10 seconds rule)
-			recentFilter := bson.M{
-				"user_id": userID.(string),
-				"song_id": songID,
-				"played_at": bson.M{
-					"$gte": time.Now().Add(-10 * time.Second),
-				},
-			}
-
-			count, _ := historyCollection.CountDocuments(ctx, recentFilter)
-			if count > 0 {
-				// Already played recently → don't increment
-				c.JSON(http.StatusOK, gin.H{"song": song})
-				return
-			}
-
-			// 3️⃣ Remove old history entry (keep latest only)
-			_, _ = historyCollection.DeleteMany(ctx, bson.M{
-				"user_id": userID.(string),
-				"song_id": songID,
-			})
-
-			// 4️⃣ Insert new history record
-			newHistory := models.History{
-				ID:       primitive.NewObjectID(),
-				UserID:   userID.(string),
-				SongID:   songID,
-				PlayedAt: time.Now(),
-			}
-			_, _ = historyCollection.InsertOne(ctx, newHistory)
-
-			// 5️⃣ Increment play counts (atomic + safe)
-			update := bson.M{
-				"$inc": bson.M{
-					"play_count":                          1,
-					"user_play_counts." + userID.(string): 1,
-				},
-			}
-
-			opts := options.Update().SetUpsert(true)
-
-			_, err := songcollection.UpdateOne(
-				ctx,
-				bson.M{"song_id": songID},
-				update,
-				opts,
-			)
-
-			// Update response object (optional but good UX)
-			if err == nil {
-				song.PlayCount++
-				if song.UserPlayCounts == nil {
-					song.UserPlayCounts = make(map[string]int)
-				}
-				song.UserPlayCounts[userID.(string)]++
-			}
+		// Songs that aren't live yet are only visible to their uploader and admins
+		if !viewerFromContext(c).canView(song) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "song not found"})
+			return
 		}
 
-		// 6️⃣ Return song
+		// Plays are recorded through /plays/start and /plays/:play_id/progress
 		c.JSON(http.StatusOK, gin.H{"song": song})
 	}
 }
@@ -126,7 +99,17 @@ func UploadSong(c *gin.Context) {
 	genre := c.PostForm("genre")
 	info := c.PostForm("info")
 	language := c.PostForm("language")
-	releaseDateStr := c.PostForm("release_date") // Expecting ISO8601 or yyyy-mm-dd
+	releaseDateStr := c.PostForm("release_date")        // Expecting ISO8601 or yyyy-mm-dd
+	duration, _ := strconv.Atoi(c.PostForm("duration")) // Optional, seconds
+	explicit := false
+	if value, ok := c.GetPostForm("explicit"); ok && value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "explicit must be true or false"})
+			return
+		}
+		explicit = parsed
+	}
 
 	// Debug: log incoming content type and form values to help troubleshooting
 	contentType := c.Request.Header.Get("Content-Type")
@@ -150,6 +133,9 @@ func UploadSong(c *gin.Context) {
 		}
 	}
 
+	// A future release_date embargoes the song until the release scheduler picks it up
+	isReleased := releaseDatePtr == nil || !releaseDatePtr.After(time.Now())
+
 	userIDInterface, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
@@ -157,6 +143,62 @@ func UploadSong(c *gin.Context) {
 	}
 	uploadedBy := userIDInterface.(string)
 
+	// Admins and trusted uploaders skip the moderation queue
+	status := models.SongStatusPending
+	if c.GetString("user_type") == "ADMIN" || isTrustedUploader(context.Background(), uploadedBy) {
+		status = models.SongStatusApproved
+	}
+
+	// Uploading straight onto an album takes the album's title
+	var targetAlbum *models.Album
+	if albumID := c.PostForm("album_id"); albumID != "" {
+		var found models.Album
+		if err := albumCollection.FindOne(context.Background(), bson.M{"album_id": albumID}).Decode(&found); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Album not found"})
+			return
+		}
+		if !canManageAlbum(c, found) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You can't add songs to this album"})
+			return
+		}
+		targetAlbum = &found
+		album = *found.Title
+	}
+
+	// Credits link the song to artist profiles; without them they're read
+	// from the artist field
+	creditInputs, hasCredits, err := creditsFromForm(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	availability, _, err := availabilityFromForm(c, nil)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Lyrics are checked before anything is uploaded so a bad LRC file fails fast
+	lyrics, err := lyricsFromForm(c, duration)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if lyrics != nil && language != "" {
+		lyrics.Language = &language
+	}
+
+	moods, err := resolveMoods(context.Background(), formList(c, "moods"))
+	if err != nil {
+		if errors.Is(err, errUnknownGenre) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve moods"})
+		return
+	}
+
 	// Upload audio
 	songFile, songHeader, err := c.Request.FormFile("song_file")
 	if err != nil {
@@ -165,6 +207,74 @@ func UploadSong(c *gin.Context) {
 	}
 	defer songFile.Close()
 
+	// 🔍 Duplicate detection before we spend time uploading to Cloudinary
+	contentHash, err := helpers.HashFile(songFile)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read song file"})
+		return
+	}
+
+	var existing models.Song
+	err = songcollection.FindOne(context.Background(), bson.M{"content_hash": contentHash}).Decode(&existing)
+	if err == nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "This song has already been uploaded",
+			"song_id": existing.SongID,
+		})
+		return
+	}
+	if err != mongo.ErrNoDocuments {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check for duplicates"})
+		return
+	}
+
+	// Genres are mapped onto the taxonomy so "Pop" and "pop " are one genre;
+	// one it doesn't have yet keeps its name and is queued for an admin
+	var genreSlug *string
+	if genre != "" {
+		normalized, err := normalizeGenre(context.Background(), genre)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve genre"})
+			return
+		}
+		genre = strings.Join(strings.Fields(genre), " ")
+		if normalized != nil {
+			genre = normalized.Name
+			genreSlug = &normalized.Slug
+		}
+	}
+
+	// Artists are only resolved once the upload looks good, so a rejected
+	// upload doesn't leave new artist profiles behind
+	if !hasCredits {
+		creditInputs, err = creditsFromArtistString(context.Background(), artist)
+		if err != nil {
+			log.Println("❌ Failed to resolve artist credits:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve artists"})
+			return
+		}
+	}
+	credits, artistIDs, err := resolveArtistCredits(context.Background(), creditInputs)
+	if err != nil {
+		if errors.Is(err, errInvalidCredit) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Println("❌ Failed to resolve artist credits:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve artists"})
+		return
+	}
+	if strings.TrimSpace(artist) == "" {
+		artist = creditsDisplayName(credits)
+	}
+
+	fingerprint := helpers.SongFingerprint(title, artist)
+	duplicateOf, err := findNearDuplicate(context.Background(), fingerprint, title, artist, duration)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check for duplicates"})
+		return
+	}
+
 	songURL, err := helpers.UploadFile(songFile, songHeader, "songs")
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload song"})
@@ -206,8 +316,41 @@ func UploadSong(c *gin.Context) {
 		SongID:      newID.Hex(),
 		ReleaseDate: releaseDatePtr,
 
+		ArtistIDs:     artistIDs,
+		ArtistCredits: credits,
+
+		Credits:   songCreditsFromFields(songCreditsFromForm(c)),
+		HasLyrics: lyrics != nil,
+		HasSynced: lyrics != nil && lyrics.Synced,
+
+		GenreSlug: genreSlug,
+		Moods:     moods,
+
+		Explicit: explicit,
+
+		Availability: availability,
+
 		PlayCount:      0,
 		UserPlayCounts: map[string]int{},
+
+		Duration:    duration,
+		ContentHash: contentHash,
+		Fingerprint: fingerprint,
+		DuplicateOf: duplicateOf,
+		NeedsReview: duplicateOf != nil,
+
+		AnalysisStatus: models.AnalysisPending,
+
+		Status: status,
+
+		IsReleased: isReleased,
+	}
+	if targetAlbum != nil {
+		song.AlbumID = &targetAlbum.AlbumID
+	}
+	if !isReleased {
+		notified := false
+		song.ReleaseNotified = &notified
 	}
 
 	_, err = songcollection.InsertOne(context.Background(), song)
@@ -216,10 +359,764 @@ func UploadSong(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	QueueSongAnalysis(song.SongID)
+	indexSong(song)
+
+	if lyrics != nil {
+		if err := saveLyrics(context.Background(), song.SongID, lyrics, uploadedBy); err != nil {
+			log.Printf("⚠️ Song %s uploaded but its lyrics weren't saved: %v\n", song.SongID, err)
+		}
+	}
+
+	if targetAlbum != nil {
+		if err := appendAlbumTrack(context.Background(), *targetAlbum, song.SongID); err != nil {
+			log.Printf("⚠️ Song %s uploaded but not added to album %s: %v\n", song.SongID, targetAlbum.AlbumID, err)
+		}
+	}
+
+	response := gin.H{
 		"message":   "Song uploaded successfully",
 		"song_data": song,
-	})
+	}
+	if status == models.SongStatusPending {
+		response["message"] = "Song uploaded successfully and is waiting for review"
+	}
+	if !isReleased {
+		response["scheduled_release"] = releaseDatePtr
+	}
+	if duplicateOf != nil {
+		log.Printf("⚠️ Song %s flagged as possible duplicate of %s\n", song.SongID, *duplicateOf)
+		response["possible_duplicate_of"] = *duplicateOf
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// findNearDuplicate returns the song_id of an existing song with the same
+// fingerprint and a matching duration, or nil if there is none. When either
+// duration is unknown only the exact same title and artist count.
+func findNearDuplicate(ctx context.Context, fingerprint string, title string, artist string, duration int) (*string, error) {
+	if fingerprint == "" {
+		return nil, nil
+	}
+	cursor, err := songcollection.Find(ctx, bson.M{"fingerprint": fingerprint},
+		options.Find().SetProjection(bson.M{"song_id": 1, "duration": 1, "title": 1, "artist": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var candidates []models.Song
+	if err := cursor.All(ctx, &candidates); err != nil {
+		return nil, err
+	}
+
+	for _, candidate := range candidates {
+		sameNames := candidate.Title != nil && candidate.Artist != nil &&
+			strings.EqualFold(strings.TrimSpace(*candidate.Title), strings.TrimSpace(title)) &&
+			strings.EqualFold(strings.TrimSpace(*candidate.Artist), strings.TrimSpace(artist))
+		unknown := candidate.Duration == 0 || duration == 0
+		if helpers.DurationsMatch(candidate.Duration, duration) || (unknown && sameNames) {
+			songID := candidate.SongID
+			return &songID, nil
+		}
+	}
+	return nil, nil
+}
+
+// songViewer is whoever is browsing the catalogue; it decides which songs they can see
+type songViewer struct {
+	UserID       string
+	IsAdmin      bool
+	HideExplicit bool
+	Country      string // ISO code the request came from, "" when unknown
+	AnyRegion    bool   // skip licensing rules, for catalogue-wide work filtered again per request
+}
+
+func viewerFromContext(c *gin.Context) songViewer {
+	viewer := songViewer{
+		UserID:  c.GetString("user_id"),
+		IsAdmin: c.GetString("user_type") == "ADMIN",
+		Country: c.GetString("country"),
+	}
+	if viewer.UserID != "" {
+		// Looked up once per request however many queries it filters
+		hide, ok := c.Get("hide_explicit")
+		if !ok {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			hide = hidesExplicit(ctx, viewer.UserID)
+			cancel()
+			c.Set("hide_explicit", hide)
+		}
+		viewer.HideExplicit = hide.(bool)
+	}
+	return viewer
+}
+
+// viewerForUser is the viewer for work done on a user's behalf outside a request
+func viewerForUser(ctx context.Context, userID string) songViewer {
+	return songViewer{UserID: userID, HideExplicit: hidesExplicit(ctx, userID)}
+}
+
+// hidesExplicit reports whether the user asked not to see explicit songs. If
+// the preference can't be read explicit songs are hidden, not shown.
+func hidesExplicit(ctx context.Context, userID string) bool {
+	var user models.User
+	err := usercollection.FindOne(ctx, bson.M{"user_id": userID},
+		options.FindOne().SetProjection(bson.M{"hide_explicit": 1})).Decode(&user)
+	if err != nil {
+		if err != mongo.ErrNoDocuments {
+			log.Println("❌ Failed to read content preference:", err)
+			return true
+		}
+		return false
+	}
+	return user.HideExplicit != nil && *user.HideExplicit
+}
+
+// songFilter restricts a song query to songs the viewer is allowed to see:
+// approved, already released and licensed for the viewer's country and the
+// current date unless the viewer uploaded it or is an admin, and not explicit
+// if the viewer hides explicit songs
+func (v songViewer) songFilter(filter bson.M) bson.M {
+	conditions := []bson.M{filter, {"status": models.SongStatusApproved}}
+	if v.HideExplicit {
+		conditions = append(conditions, bson.M{"explicit": bson.M{"$ne": true}})
+	}
+
+	if !v.IsAdmin {
+		now := time.Now()
+		released := []bson.M{
+			{"is_released": true},
+			{"release_date": bson.M{"$lte": now}},
+		}
+		if v.UserID != "" {
+			released = append(released, bson.M{"uploaded_by": v.UserID})
+		}
+		conditions = append(conditions, bson.M{"$or": released})
+
+		if !v.AnyRegion {
+			available := []bson.M{v.availabilityFilter(now)}
+			if v.UserID != "" {
+				available = append(available, bson.M{"uploaded_by": v.UserID})
+			}
+			conditions = append(conditions, bson.M{"$or": available})
+		}
+	}
+
+	return bson.M{"$and": conditions}
+}
+
+// availabilityFilter is SongAvailability.AvailableIn as a query
+func (v songViewer) availabilityFilter(now time.Time) bson.M {
+	allowed := bson.A{bson.M{"availability.allowed_countries.0": bson.M{"$exists": false}}}
+	if v.Country != "" {
+		allowed = append(allowed, bson.M{"availability.allowed_countries": v.Country})
+	}
+	conditions := bson.A{
+		bson.M{"availability.starts_at": bson.M{"$not": bson.M{"$gt": now}}},
+		bson.M{"availability.ends_at": bson.M{"$not": bson.M{"$lte": now}}},
+		bson.M{"$or": allowed},
+	}
+	if v.Country != "" {
+		conditions = append(conditions, bson.M{"availability.blocked_countries": bson.M{"$ne": v.Country}})
+	}
+	return bson.M{"$and": conditions}
+}
+
+// availableTo reports whether licensing lets the viewer play the song
+func (v songViewer) availableTo(song models.Song) bool {
+	if v.IsAdmin || v.AnyRegion || (song.UploadedBy != nil && *song.UploadedBy == v.UserID && v.UserID != "") {
+		return true
+	}
+	return song.Availability.AvailableIn(v.Country, time.Now())
+}
+
+// canView is songFilter for a song that has already been fetched
+func (v songViewer) canView(song models.Song) bool {
+	if v.HideExplicit && song.Explicit {
+		return false
+	}
+	if v.IsAdmin || (song.UploadedBy != nil && *song.UploadedBy == v.UserID && v.UserID != "") {
+		return true
+	}
+	if song.Status != models.SongStatusApproved || !v.availableTo(song) {
+		return false
+	}
+	return song.IsReleased || song.ReleaseDate == nil || !song.ReleaseDate.After(time.Now())
+}
+
+// publicSongFilter restricts a song query to what the current request may see
+func publicSongFilter(c *gin.Context, filter bson.M) bson.M {
+	return viewerFromContext(c).songFilter(filter)
+}
+
+// isTrustedUploader reports whether an admin has marked the user as a trusted uploader
+func isTrustedUploader(ctx context.Context, userID string) bool {
+	var user models.User
+	err := usercollection.FindOne(ctx, bson.M{"user_id": userID},
+		options.FindOne().SetProjection(bson.M{"trusted_uploader": 1})).Decode(&user)
+	return err == nil && user.TrustedUploader
+}
+
+// canManageSong reports whether the logged-in user uploaded the song or is an admin
+func canManageSong(c *gin.Context, song models.Song) bool {
+	if c.GetString("user_type") == "ADMIN" {
+		return true
+	}
+	return song.UploadedBy != nil && *song.UploadedBy == c.GetString("user_id")
+}
+
+// UpdateSong lets the uploader (or an admin) fix song metadata and artwork.
+// Accepts multipart form data; only the fields that are sent are changed.
+func UpdateSong() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log.Println("🔹 UpdateSong endpoint hit")
+
+		songID := c.Param("song_id")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		var song models.Song
+		err := songcollection.FindOne(ctx, bson.M{"song_id": songID}).Decode(&song)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Song not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch song"})
+			return
+		}
+
+		if !canManageSong(c, song) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to update this song"})
+			return
+		}
+
+		if err := c.Request.ParseMultipartForm(20 << 20); err != nil && err != http.ErrNotMultipart {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form data"})
+			return
+		}
+
+		updateFields := bson.M{}
+
+		// The album title of a linked song follows the album
+		if _, ok := c.GetPostForm("album"); ok && song.AlbumID != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "This song is on an album; edit the album or its track list instead"})
+			return
+		}
+
+		// Plain text fields
+		for _, field := range []string{"album", "info", "language"} {
+			if value, ok := c.GetPostForm(field); ok {
+				updateFields[field] = strings.TrimSpace(value)
+			}
+		}
+
+		if value, ok := c.GetPostForm("genre"); ok {
+			genre, err := normalizeGenre(ctx, value)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve genre"})
+				return
+			}
+			updateFields["genre"] = strings.Join(strings.Fields(value), " ")
+			updateFields["genre_slug"] = nil
+			if genre != nil {
+				updateFields["genre"] = genre.Name
+				updateFields["genre_slug"] = genre.Slug
+			}
+		}
+		if _, ok := c.GetPostForm("moods"); ok {
+			moods, err := resolveMoods(ctx, formList(c, "moods"))
+			if err != nil {
+				if errors.Is(err, errUnknownGenre) {
+					c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve moods"})
+				return
+			}
+			updateFields["moods"] = moods
+		}
+
+		// Uploaders can mark their song explicit; only a moderator can clear the flag
+		if value, ok := c.GetPostForm("explicit"); ok {
+			explicit, err := strconv.ParseBool(value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "explicit must be true or false"})
+				return
+			}
+			if song.Explicit && !explicit && c.GetString("user_type") != "ADMIN" {
+				c.JSON(http.StatusForbidden, gin.H{"error": "Only a moderator can clear the explicit flag"})
+				return
+			}
+			updateFields["explicit"] = explicit
+		}
+
+		availability, changed, err := availabilityFromForm(c, song.Availability)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if changed {
+			updateFields["availability"] = availability
+		}
+
+		// Title and artist can't be blanked, and they feed the duplicate fingerprint
+		// Older songs can be missing either one; treat that as empty
+		var currentTitle, currentArtist string
+		if song.Title != nil {
+			currentTitle = *song.Title
+		}
+		if song.Artist != nil {
+			currentArtist = *song.Artist
+		}
+		title := currentTitle
+		artist := currentArtist
+		if value, ok := c.GetPostForm("title"); ok {
+			title = strings.TrimSpace(value)
+			if len(title) < 2 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Title must be at least 2 characters"})
+				return
+			}
+			updateFields["title"] = title
+		}
+		if value, ok := c.GetPostForm("artist"); ok {
+			artist = strings.TrimSpace(value)
+			if len(artist) < 2 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Artist must be at least 2 characters"})
+				return
+			}
+			updateFields["artist"] = artist
+		}
+
+		// New credits replace the old ones; a new artist line re-credits the
+		// primary artists (and featured ones, if it names any) and keeps
+		// everyone else
+		creditInputs, hasCredits, err := creditsFromForm(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if !hasCredits && artist != currentArtist {
+			creditInputs, err = creditsFromArtistString(ctx, artist)
+			if err != nil {
+				log.Println("❌ Failed to resolve artist credits:", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve artists"})
+				return
+			}
+			_, featured := helpers.SplitFeaturedArtists(artist)
+			for _, credit := range song.ArtistCredits {
+				if credit.Role == models.ArtistRolePrimary || (credit.Role == models.ArtistRoleFeatured && featured != "") {
+					continue
+				}
+				creditInputs = append(creditInputs, creditInput{ArtistID: credit.ArtistID, Role: credit.Role})
+			}
+			hasCredits = true
+		}
+		if hasCredits {
+			credits, artistIDs, err := resolveArtistCredits(ctx, creditInputs)
+			if err != nil {
+				if errors.Is(err, errInvalidCredit) {
+					c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+				log.Println("❌ Failed to resolve artist credits:", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve artists"})
+				return
+			}
+			updateFields["artist_credits"] = credits
+			updateFields["artist_ids"] = artistIDs
+
+			if _, ok := c.GetPostForm("artist"); !ok {
+				if display := creditsDisplayName(credits); display != "" {
+					artist = display
+					updateFields["artist"] = artist
+				}
+			}
+		}
+
+		if title != currentTitle || artist != currentArtist {
+			updateFields["fingerprint"] = helpers.SongFingerprint(title, artist)
+		}
+
+		// Writers, producers, composers and labels, each replaced only when sent
+		for key, names := range songCreditsFromForm(c) {
+			updateFields["credits."+key] = names
+		}
+
+		if value, ok := c.GetPostForm("release_date"); ok {
+			var releaseDate *time.Time
+			if value != "" {
+				if t, err := time.Parse(time.RFC3339, value); err == nil {
+					releaseDate = &t
+				} else if t, err := time.Parse("2006-01-02", value); err == nil {
+					releaseDate = &t
+				} else {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "release_date must be RFC3339 or yyyy-mm-dd"})
+					return
+				}
+			}
+			updateFields["release_date"] = releaseDate
+
+			// A song that is already out stays out; otherwise the new date decides
+			if !song.IsReleased {
+				updateFields["is_released"] = releaseDate == nil || !releaseDate.After(time.Now())
+			}
+		}
+
+		if value, ok := c.GetPostForm("duration"); ok {
+			duration, err := strconv.Atoi(value)
+			if err != nil || duration < 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "duration must be a number of seconds"})
+				return
+			}
+			updateFields["duration"] = duration
+		}
+
+		// Optional new artwork
+		var oldImageURL *string
+		imageFile, imageHeader, err := c.Request.FormFile("image_file")
+		if err == nil {
+			defer imageFile.Close()
+			imgURL, err := helpers.UploadFile(imageFile, imageHeader, "song_images")
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image"})
+				return
+			}
+			updateFields["image_url"] = imgURL
+			oldImageURL = song.ImageURL
+		}
+
+		if len(updateFields) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "No valid fields to update"})
+			return
+		}
+
+		// A rejected song goes back into the queue once the uploader fixes it
+		if song.Status == models.SongStatusRejected && c.GetString("user_type") != "ADMIN" {
+			updateFields["status"] = models.SongStatusPending
+		}
+
+		updateFields["updated_at"] = time.Now()
+
+		var updatedSong models.Song
+		err = songcollection.FindOneAndUpdate(ctx,
+			bson.M{"song_id": songID},
+			bson.M{"$set": updateFields},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&updatedSong)
+		if err != nil {
+			log.Println("❌ Failed to update song:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update song"})
+			return
+		}
+
+		// Old artwork is no longer referenced by this song
+		if oldImageURL != nil && *oldImageURL != "" {
+			if err := deleteUnusedFile(ctx, *oldImageURL); err != nil {
+				log.Printf("⚠️ Failed to delete old artwork for song %s: %v\n", songID, err)
+			}
+		}
+
+		indexSong(updatedSong)
+
+		log.Printf("✅ Song %s updated\n", songID)
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Song updated successfully",
+			"song":    updatedSong,
+		})
+	}
+}
+
+// DeleteSong removes a song, its stored media and every reference to it (uploader or admin)
+func DeleteSong() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log.Println("🔹 DeleteSong endpoint hit")
+
+		songID := c.Param("song_id")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		var song models.Song
+		err := songcollection.FindOne(ctx, bson.M{"song_id": songID}).Decode(&song)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Song not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch song"})
+			return
+		}
+
+		if !canManageSong(c, song) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to delete this song"})
+			return
+		}
+
+		// Likes and saves live on the song document, so they go with it
+		if _, err := songcollection.DeleteOne(ctx, bson.M{"song_id": songID}); err != nil {
+			log.Println("❌ Failed to delete song:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete song"})
+			return
+		}
+
+		if err := removeSongReferences(ctx, songID); err != nil {
+			log.Printf("⚠️ Song %s deleted but cleanup failed: %v\n", songID, err)
+		}
+		unindexSong(songID)
+
+		// Stored media is removed last and best-effort; the song is already gone
+		if song.FileURL != nil && *song.FileURL != "" {
+			if err := deleteUnusedFile(ctx, *song.FileURL); err != nil {
+				log.Printf("⚠️ Failed to delete audio for song %s: %v\n", songID, err)
+			}
+		}
+		if song.ImageURL != nil && *song.ImageURL != "" {
+			if err := deleteUnusedFile(ctx, *song.ImageURL); err != nil {
+				log.Printf("⚠️ Failed to delete artwork for song %s: %v\n", songID, err)
+			}
+		}
+
+		log.Printf("✅ Song %s deleted\n", songID)
+		c.JSON(http.StatusOK, gin.H{"message": "Song deleted successfully"})
+	}
+}
+
+// deleteUnusedFile removes stored media once no song, album, playlist or
+// artist points at it any more. Older uploads were named after the file alone,
+// so several documents can share one asset.
+func deleteUnusedFile(ctx context.Context, fileURL string) error {
+	checks := []struct {
+		collection *mongo.Collection
+		filter     bson.M
+	}{
+		{songcollection, bson.M{"$or": []bson.M{{"file_url": fileURL}, {"image_url": fileURL}}}},
+		{albumCollection, bson.M{"cover_url": fileURL}},
+		{playlistCollection, bson.M{"cover_image": fileURL}},
+		{artistCollection, bson.M{"image_url": fileURL}},
+	}
+	for _, check := range checks {
+		count, err := check.collection.CountDocuments(ctx, check.filter, options.Count().SetLimit(1))
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+	}
+	return helpers.DeleteFile(fileURL)
+}
+
+// moveSongReferences points everything that refers to a duplicate at the song
+// it is merged into: the same collections removeSongReferences cleans. It
+// returns how many history entries and playlists changed.
+func moveSongReferences(ctx context.Context, source models.Song, target models.Song) (int64, int64, error) {
+	now := time.Now()
+	from, to := source.SongID, target.SongID
+
+	// Comments follow the song; comment_count moves with the counters
+	if _, err := commentCollection.UpdateMany(ctx,
+		bson.M{"song_id": from},
+		bson.M{"$set": bson.M{"song_id": to}},
+	); err != nil {
+		return 0, 0, err
+	}
+
+	if err := moveSongReactions(ctx, from, to); err != nil {
+		return 0, 0, err
+	}
+
+	// Lyrics only when the survivor has none of its own
+	if !target.HasLyrics && source.HasLyrics {
+		if _, err := lyricsCollection.UpdateOne(ctx,
+			bson.M{"song_id": from},
+			bson.M{"$set": bson.M{"song_id": to}},
+		); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	historyResult, err := historyCollection.UpdateMany(ctx,
+		bson.M{"song_id": from},
+		bson.M{"$set": bson.M{"song_id": to}},
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Playlists: drop the duplicate where the target is already present,
+	// otherwise swap it in place so track order is kept
+	if _, err := playlistCollection.UpdateMany(ctx,
+		bson.M{"song_ids": bson.M{"$all": []string{from, to}}},
+		bson.M{"$pull": bson.M{"song_ids": from}, "$set": bson.M{"updated_at": now}},
+	); err != nil {
+		return 0, 0, err
+	}
+	playlistResult, err := playlistCollection.UpdateMany(ctx,
+		bson.M{"song_ids": from},
+		bson.M{"$set": bson.M{"song_ids.$": to, "updated_at": now}},
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Albums: a song sits on one album only, so the survivor takes the
+	// duplicate's track slot unless it already has an album of its own
+	if target.AlbumID != nil {
+		_, err = albumCollection.UpdateMany(ctx,
+			bson.M{"tracks.song_id": from},
+			bson.M{"$pull": bson.M{"tracks": bson.M{"song_id": from}}, "$set": bson.M{"updated_at": now}},
+		)
+	} else {
+		_, err = albumCollection.UpdateMany(ctx,
+			bson.M{"tracks.song_id": from},
+			bson.M{"$set": bson.M{"tracks.$.song_id": to, "updated_at": now}},
+		)
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Similarities are rebuilt from play history, which now counts for the
+	// survivor; until then the duplicate just drops out
+	if _, err := similarityCollection.DeleteOne(ctx, bson.M{"song_id": from}); err != nil {
+		return 0, 0, err
+	}
+	if _, err := similarityCollection.UpdateMany(ctx,
+		bson.M{"similar.song_id": from},
+		bson.M{"$pull": bson.M{"similar": bson.M{"song_id": from}}},
+	); err != nil {
+		return 0, 0, err
+	}
+
+	// Devices pick up the change through the version bump
+	for _, field := range []string{"up_next", "queue", "history", "source_songs"} {
+		if err := replaceInArray(ctx, playbackCollection, field, from, to, bson.M{"version": 1}); err != nil {
+			return 0, 0, err
+		}
+	}
+	if _, err := playbackCollection.UpdateMany(ctx,
+		bson.M{"song_id": from},
+		bson.M{"$set": bson.M{"song_id": to, "updated_at": now}, "$inc": bson.M{"version": 1}},
+	); err != nil {
+		return 0, 0, err
+	}
+
+	if _, err := radioCollection.UpdateMany(ctx,
+		bson.M{"seed_type": models.RadioSeedSong, "seed_id": from},
+		bson.M{"$set": bson.M{"seed_id": to, "updated_at": now}},
+	); err != nil {
+		return 0, 0, err
+	}
+	for _, field := range []string{"played", "liked", "disliked"} {
+		if err := replaceInArray(ctx, radioCollection, field, from, to, nil); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	// Anything that was flagged against the duplicate now points at the survivor
+	if _, err := songcollection.UpdateOne(ctx,
+		bson.M{"song_id": to, "duplicate_of": from},
+		bson.M{"$set": bson.M{"needs_review": false}, "$unset": bson.M{"duplicate_of": ""}},
+	); err != nil {
+		return 0, 0, err
+	}
+	if _, err := songcollection.UpdateMany(ctx,
+		bson.M{"duplicate_of": from},
+		bson.M{"$set": bson.M{"duplicate_of": to}},
+	); err != nil {
+		return 0, 0, err
+	}
+
+	return historyResult.ModifiedCount, playlistResult.ModifiedCount, nil
+}
+
+// replaceInArray swaps every occurrence of from for to in an array field,
+// touching updated_at and applying inc (if any) to the documents it changes
+func replaceInArray(ctx context.Context, collection *mongo.Collection, field string, from string, to string, inc bson.M) error {
+	update := bson.M{"$set": bson.M{field + ".$[id]": to, "updated_at": time.Now()}}
+	if inc != nil {
+		update["$inc"] = inc
+	}
+
+	_, err := collection.UpdateMany(ctx,
+		bson.M{field: from},
+		update,
+		options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{bson.M{"id": from}}}),
+	)
+	return err
+}
+
+// removeSongReferences cleans a deleted song out of every other collection
+func removeSongReferences(ctx context.Context, songID string) error {
+	now := time.Now()
+
+	if _, err := playlistCollection.UpdateMany(ctx,
+		bson.M{"song_ids": songID},
+		bson.M{"$pull": bson.M{"song_ids": songID}, "$set": bson.M{"updated_at": now}},
+	); err != nil {
+		return err
+	}
+
+	if _, err := historyCollection.DeleteMany(ctx, bson.M{"song_id": songID}); err != nil {
+		return err
+	}
+
+	if _, err := reactionCollection.DeleteMany(ctx, bson.M{"song_id": songID}); err != nil {
+		return err
+	}
+
+	if _, err := lyricsCollection.DeleteOne(ctx, bson.M{"song_id": songID}); err != nil {
+		return err
+	}
+
+	if _, err := commentCollection.DeleteMany(ctx, bson.M{"song_id": songID}); err != nil {
+		return err
+	}
+
+	if _, err := similarityCollection.DeleteOne(ctx, bson.M{"song_id": songID}); err != nil {
+		return err
+	}
+
+	// Devices pick up the change through the version bump
+	if _, err := playbackCollection.UpdateMany(ctx,
+		bson.M{"$or": bson.A{
+			bson.M{"up_next": songID}, bson.M{"queue": songID}, bson.M{"history": songID}, bson.M{"source_songs": songID},
+		}},
+		bson.M{
+			"$pull": bson.M{"up_next": songID, "queue": songID, "history": songID, "source_songs": songID},
+			"$inc":  bson.M{"version": 1},
+			"$set":  bson.M{"updated_at": now},
+		},
+	); err != nil {
+		return err
+	}
+	if _, err := playbackCollection.UpdateMany(ctx,
+		bson.M{"song_id": songID},
+		bson.M{"$set": bson.M{"song_id": nil, "position": 0, "is_playing": false, "updated_at": now}, "$inc": bson.M{"version": 1}},
+	); err != nil {
+		return err
+	}
+
+	if _, err := albumCollection.UpdateMany(ctx,
+		bson.M{"tracks.song_id": songID},
+		bson.M{"$pull": bson.M{"tracks": bson.M{"song_id": songID}}, "$set": bson.M{"updated_at": now}},
+	); err != nil {
+		return err
+	}
+
+	// Near-duplicates flagged against this song have nothing left to compare to
+	if _, err := songcollection.UpdateMany(ctx,
+		bson.M{"duplicate_of": songID},
+		bson.M{"$set": bson.M{"needs_review": false}, "$unset": bson.M{"duplicate_of": ""}},
+	); err != nil {
+		return err
+	}
+
+	return nil
 }
 
 func GetAllSongs(c *gin.Context) {
@@ -233,7 +1130,7 @@ func GetAllSongs(c *gin.Context) {
 
 	var songs []models.Song
 
-	cursor, err := songcollection.Find(context.Background(), bson.M{})
+	cursor, err := songcollection.Find(context.Background(), publicSongFilter(c, bson.M{}))
 	if err != nil {
 		log.Println("❌ Failed to fetch songs:", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch songs"})
@@ -285,36 +1182,26 @@ func ToggleLikeSong(c *gin.Context) {
 	userId := c.GetString("user_id")
 	songId := c.Param("song_id")
 
-	var song models.Song
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-	err := songcollection.FindOne(context.Background(), bson.M{"song_id": songId}).Decode(&song)
+	count, err := songcollection.CountDocuments(ctx, publicSongFilter(c, bson.M{"song_id": songId}))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch song"})
 		return
 	}
-
-	alreadyLiked := false
-	for _, id := range song.Likes {
-		if id == userId {
-			alreadyLiked = true
-			break
-		}
-	}
-
-	var update bson.M
-	if alreadyLiked {
-		update = bson.M{"$pull": bson.M{"likes": userId}}
-	} else {
-		update = bson.M{"$addToSet": bson.M{"likes": userId}}
+	if count == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Song not found"})
+		return
 	}
 
-	_, err = songcollection.UpdateOne(context.Background(), bson.M{"song_id": songId}, update)
+	liked, err := toggleSongReaction(ctx, songId, userId, models.ReactionLike)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update like"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "like toggled"})
+	c.JSON(http.StatusOK, gin.H{"message": "like toggled", "liked": liked})
 }
 
 func ToggleSave(c *gin.Context) {
@@ -327,39 +1214,17 @@ func ToggleSave(c *gin.Context) {
 
 	songID := c.Param("song_id")
 
-	// Find the song in DB
-	var song models.Song
-	err := songcollection.FindOne(context.TODO(), bson.M{"song_id": songID}).Decode(&song)
-	if err != nil {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Make sure the song exists and is visible to this user
+	count, err := songcollection.CountDocuments(ctx, publicSongFilter(c, bson.M{"song_id": songID}))
+	if err != nil || count == 0 {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Song not found"})
 		return
 	}
 
-	// Check if user already saved the song
-	alreadySaved := false
-	for _, id := range song.Saves {
-		if id == userId {
-			alreadySaved = true
-			break
-		}
-	}
-
-	var update bson.M
-
-	if alreadySaved {
-		update = bson.M{
-			"$pull": bson.M{"saves": userId},
-		}
-	} else {
-		update = bson.M{
-			"$addToSet": bson.M{"saves": userId},
-		}
-	}
-
-	// Update MongoDB
-	_, err = songcollection.UpdateOne(context.TODO(),
-		bson.M{"song_id": songID}, update)
-
+	saved, err := toggleSongReaction(ctx, songID, userId, models.ReactionSave)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update save"})
 		return
@@ -367,67 +1232,23 @@ func ToggleSave(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Save updated",
-		"saved":   !alreadySaved,
+		"saved":   saved,
 	})
 }
 
+// MostLikedSongs returns the top 10 songs by likes, of all time or ?window=week|month
 func MostLikedSongs() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		log.Println("🔹 MostLikedSongs endpoint hit")
-
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-
-		var songs []models.Song
-
-		findOptions := options.Find().
-			SetSort(bson.D{{Key: "likes", Value: -1}}). // Sort: highest likes first
-			SetLimit(10)                                // Limit: top 10
-
-		cursor, err := songcollection.Find(ctx, bson.M{}, findOptions)
-		if err != nil {
-			log.Println("❌ Failed to fetch songs:", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch songs"})
-			return
-		}
-		defer cursor.Close(ctx)
-
-		if err := cursor.All(ctx, &songs); err != nil {
-			log.Println("❌ Failed to parse songs:", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse songs"})
-			return
-		}
-
-		log.Printf("✅ Successfully fetched %d most liked songs\n", len(songs))
-		c.JSON(http.StatusOK, gin.H{"songs": songs})
+		topReactedSongs(c, models.ReactionLike)
 	}
 }
 
+// MostSavedSongs returns the top 10 songs by saves, of all time or ?window=week|month
 func MostSavedSongs() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		log.Println("🔹 MostSavedSongs endpoint hit")
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-		var songs []models.Song
-
-		findOptions := options.Find().
-			SetSort(bson.D{{Key: "saves", Value: -1}}). // Sort: highest saves first
-			SetLimit(10)                                // Limit: top 10
-		cursor, err := songcollection.Find(ctx, bson.M{}, findOptions)
-		if err != nil {
-			log.Println("❌ Failed to fetch songs:", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch songs"})
-			return
-		}
-		defer cursor.Close(ctx)
-
-		if err := cursor.All(ctx, &songs); err != nil {
-			log.Println("❌ Failed to parse songs:", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse songs"})
-			return
-		}
-		log.Printf("✅ Successfully fetched %d most saved songs\n", len(songs))
-		c.JSON(http.StatusOK, gin.H{"songs": songs})
+		topReactedSongs(c, models.ReactionSave)
 	}
 }
 
@@ -479,7 +1300,7 @@ func SearchSongs(c *gin.Context) {
 
 	var songs []models.Song
 
-	cursor, err := songcollection.Find(context.Background(), filter)
+	cursor, err := songcollection.Find(context.Background(), publicSongFilter(c, filter))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Error fetching songs",
@@ -522,7 +1343,7 @@ func MylikedSongs() gin.HandlerFunc {
 		var songs []models.Song
 		filter := bson.M{"likes": likedBy}
 
-		cursor, err := songcollection.Find(context.Background(), filter)
+		cursor, err := songcollection.Find(context.Background(), publicSongFilter(c, filter))
 		if err != nil {
 			log.Println("❌ Failed to fetch songs:", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch songs"})
@@ -555,7 +1376,7 @@ func MysavedSongs() gin.HandlerFunc {
 		var songs []models.Song
 		filter := bson.M{"saves": savedBy}
 
-		cursor, err := songcollection.Find(context.Background(), filter)
+		cursor, err := songcollection.Find(context.Background(), publicSongFilter(c, filter))
 		if err != nil {
 			log.Println("❌ Failed to fetch songs:", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch songs"})
@@ -573,77 +1394,59 @@ func MysavedSongs() gin.HandlerFunc {
 	}
 }
 
-func TrendingSongs() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		var songs []models.Song
-
-		opts := options.Find().
-			SetSort(bson.D{{Key: "play_count", Value: -1}}).
-			SetLimit(10)
-
-		cursor, err := songcollection.Find(context.Background(), bson.M{}, opts)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch trending songs"})
-			return
-		}
-
-		if err := cursor.All(context.Background(), &songs); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to parse songs"})
-			return
-		}
-
-		c.JSON(http.StatusOK, gin.H{"songs": songs})
-	}
-}
-
+// PunjabiSongs is kept for older clients in its original {"songs": [...]}
+// shape; new clients use /music/songs?language=punjabi
 func PunjabiSongs() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		log.Println("🔹 PunjabiSongs endpoint hit")
-
-		var songs []models.Song
-		filter := bson.M{"language": "punjabi"}
-
-		cursor, err := songcollection.Find(context.Background(), filter)
-
-		if err != nil {
-			log.Println("❌ Failed to fetch songs:", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch songs"})
-			return
-		}
-
-		if err = cursor.All(context.Background(), &songs); err != nil {
-			log.Println("❌ Failed to parse songs:", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to parse songs"})
-			return
-		}
-
-		log.Printf("✅ Successfully fetched %d Punjabi songs\n", len(songs))
-		c.JSON(http.StatusOK, gin.H{"songs": songs})
+		languageSongs(c, "punjabi")
 	}
 }
 
+// HindiSongs is kept for older clients; see PunjabiSongs
 func HindiSongs() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		log.Println("🔹 HindiSongs endpoint hit")
+		languageSongs(c, "hindi")
+	}
+}
 
-		var songs []models.Song
-		filter := bson.M{"language": "hindi"}
-		cursor, err := songcollection.Find(context.Background(), filter)
-		if err != nil {
-			log.Println("❌ Failed to fetch songs:", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch songs"})
-			return
-		}
+// languageSongs lists the newest visible songs in one language, up to
+// ?limit= (default 20, at most 100)
+func languageSongs(c *gin.Context, language string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-		if err = cursor.All(context.Background(), &songs); err != nil {
-			log.Println("❌ Failed to parse songs:", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to parse songs"})
-			return
-		}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
 
-		log.Printf("✅ Successfully fetched %d Hindi songs\n", len(songs))
-		c.JSON(http.StatusOK, gin.H{"songs": songs})
+	filter := bson.M{"language": bson.M{"$in": exactPatterns([]string{language})}}
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(limit)).
+		SetProjection(bson.M{"waveform": 0, "user_play_counts": 0})
+
+	cursor, err := songcollection.Find(ctx, publicSongFilter(c, filter), findOptions)
+	if err != nil {
+		log.Println("❌ Failed to fetch songs:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch songs"})
+		return
 	}
+
+	var songs []models.Song
+	if err = cursor.All(ctx, &songs); err != nil {
+		log.Println("❌ Failed to parse songs:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to parse songs"})
+		return
+	}
+
+	log.Printf("✅ Successfully fetched %d %s songs\n", len(songs), language)
+	c.JSON(http.StatusOK, gin.H{"songs": songs})
 }
 
 func LatestRelaseSongs() gin.HandlerFunc {
@@ -660,7 +1463,7 @@ func LatestRelaseSongs() gin.HandlerFunc {
 			SetSort(bson.D{{Key: "release_date", Value: -1}}).
 			SetLimit(10)
 
-		cursor, err := songcollection.Find(ctx, bson.M{}, findOptions)
+		cursor, err := songcollection.Find(ctx, publicSongFilter(c, bson.M{}), findOptions)
 		if err != nil {
 			log.Println("❌ Failed to fetch songs:", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch latest songs"})
@@ -694,7 +1497,7 @@ func TopSongsByUser() gin.HandlerFunc {
 			SetSort(bson.D{{Key: "user_play_counts." + userID, Value: -1}}).
 			SetLimit(10)
 
-		cursor, err := songcollection.Find(context.Background(), bson.M{"user_play_counts." + userID: bson.M{"$exists": true}}, findOptions)
+		cursor, err := songcollection.Find(context.Background(), publicSongFilter(c, bson.M{"user_play_counts." + userID: bson.M{"$exists": true}}), findOptions)
 		if err != nil {
 			log.Println("❌ Failed to fetch songs:", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch songs"})
@@ -744,7 +1547,7 @@ func AutocompleteSearch(c *gin.Context) {
 	var songs []models.Song
 	findOptions := options.Find().SetLimit(10) // Limit to 10 suggestions
 
-	cursor, err := songcollection.Find(context.Background(), filter, findOptions)
+	cursor, err := songcollection.Find(context.Background(), publicSongFilter(c, filter), findOptions)
 	if err != nil {
 		log.Println("❌ Failed to fetch suggestions:", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch suggestions"})
@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ishanbagra18/ecommerce-using-go/database"
+	"github.com/ishanbagra18/ecommerce-using-go/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var notificationCollection *mongo.Collection
+
+func InitNotificationController() {
+	notificationCollection = database.OpenCollection(database.Client, "notifications")
+	log.Println("✔ Notification collection initialized")
+
+	database.CreateIndexes(notificationCollection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "notification_id", Value: 1}}},
+	})
+}
+
+// createNotifications inserts the same kind of notification for many users at once
+func createNotifications(ctx context.Context, userIDs []string, template models.Notification) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	docs := make([]interface{}, 0, len(userIDs))
+	for _, userID := range userIDs {
+		n := template
+		n.ID = primitive.NewObjectID()
+		n.NotificationID = n.ID.Hex()
+		n.UserID = userID
+		n.Read = false
+		n.CreatedAt = now
+		docs = append(docs, n)
+	}
+
+	_, err := notificationCollection.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	return err
+}
+
+// GetMyNotifications returns the latest notifications of the logged-in user
+func GetMyNotifications() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("user_id")
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		filter := bson.M{"user_id": userID}
+		if c.Query("unread") == "true" {
+			filter["read"] = false
+		}
+
+		opts := options.Find().
+			SetSort(bson.D{{Key: "created_at", Value: -1}}).
+			SetLimit(50)
+
+		cursor, err := notificationCollection.Find(ctx, filter, opts)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notifications"})
+			return
+		}
+		defer cursor.Close(ctx)
+
+		var notifications []models.Notification
+		if err := cursor.All(ctx, &notifications); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse notifications"})
+			return
+		}
+
+		if notifications == nil {
+			notifications = []models.Notification{}
+		}
+
+		unread, _ := notificationCollection.CountDocuments(ctx, bson.M{"user_id": userID, "read": false})
+
+		c.JSON(http.StatusOK, gin.H{
+			"notifications": notifications,
+			"unread_count":  unread,
+		})
+	}
+}
+
+// MarkNotificationRead marks a single notification as read
+func MarkNotificationRead() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("user_id")
+		notificationID := c.Param("notification_id")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		result, err := notificationCollection.UpdateOne(ctx,
+			bson.M{"notification_id": notificationID, "user_id": userID},
+			bson.M{"$set": bson.M{"read": true}},
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notification"})
+			return
+		}
+
+		if result.MatchedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Notification marked as read"})
+	}
+}
+
+// MarkAllNotificationsRead marks every notification of the logged-in user as read
+func MarkAllNotificationsRead() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("user_id")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		result, err := notificationCollection.UpdateMany(ctx,
+			bson.M{"user_id": userID, "read": false},
+			bson.M{"$set": bson.M{"read": true}},
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notifications"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "All notifications marked as read",
+			"updated": result.ModifiedCount,
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ishanbagra18/ecommerce-using-go/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// playThreshold is how many seconds of listening make a session count as a play,
+// from PLAY_COUNT_THRESHOLD_SECONDS (default 30)
+func playThreshold() int {
+	if seconds, err := strconv.Atoi(os.Getenv("PLAY_COUNT_THRESHOLD_SECONDS")); err == nil && seconds > 0 {
+		return seconds
+	}
+	return 30
+}
+
+// StartPlay opens a play session when the user presses play. Nothing is
+// counted yet; progress updates decide whether it becomes a play.
+//
+//	POST /plays/start {"song_id": "...", "source": "playlist", "source_id": "...", "device": "android"}
+func StartPlay() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("user_id")
+
+		var body struct {
+			SongID   string  `json:"song_id" binding:"required"`
+			Source   string  `json:"source"`
+			SourceID *string `json:"source_id"`
+			Device   string  `json:"device" binding:"max=50"`
+		}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "song_id is required"})
+			return
+		}
+
+		if body.Source == "" {
+			body.Source = "other"
+		}
+		if !containsString(models.PlaySources, body.Source) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid source", "allowed": models.PlaySources})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		count, err := songcollection.CountDocuments(ctx, publicSongFilter(c, bson.M{"song_id": body.SongID}))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch song"})
+			return
+		}
+		if count == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "song not found"})
+			return
+		}
+
+		now := time.Now()
+		play := models.History{
+			ID:        primitive.NewObjectID(),
+			UserID:    userID,
+			SongID:    body.SongID,
+			PlayedAt:  now,
+			Source:    body.Source,
+			SourceID:  body.SourceID,
+			Device:    body.Device,
+			UpdatedAt: now,
+		}
+		if _, err := historyCollection.InsertOne(ctx, play); err != nil {
+			log.Println("❌ Failed to start play:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start play"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"play_id":           play.ID.Hex(),
+			"threshold_seconds": playThreshold(),
+		})
+	}
+}
+
+// UpdatePlayProgress records how long the user has listened so far. Once the
+// threshold is reached the play is counted, exactly once per session.
+// Send "ended": true when playback stops; the session can't be updated after that.
+//
+//	POST /plays/:play_id/progress {"listened_seconds": 42, "ended": false}
+func UpdatePlayProgress() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("user_id")
+
+		playID, err := primitive.ObjectIDFromHex(c.Param("play_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid play id"})
+			return
+		}
+
+		var body struct {
+			ListenedSeconds *int `json:"listened_seconds" binding:"required,min=0"`
+			Ended           bool `json:"ended"`
+		}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "listened_seconds is required"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var play models.History
+		err = historyCollection.FindOne(ctx, bson.M{"_id": playID, "user_id": userID}).Decode(&play)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				c.JSON(http.StatusNotFound, gin.H{"error": "play not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch play"})
+			return
+		}
+		if play.Ended {
+			c.JSON(http.StatusConflict, gin.H{"error": "play already ended"})
+			return
+		}
+
+		var song models.Song
+		err = songcollection.FindOne(ctx, bson.M{"song_id": play.SongID},
+			options.FindOne().SetProjection(bson.M{"duration": 1}),
+		).Decode(&song)
+		if err != nil && err != mongo.ErrNoDocuments {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch song"})
+			return
+		}
+
+		// Progress only moves forward and can't outrun the clock or the song
+		listened := *body.ListenedSeconds
+		if listened < play.Duration {
+			listened = play.Duration
+		}
+		if elapsed := int(time.Since(play.PlayedAt).Seconds()) + 5; listened > elapsed {
+			listened = elapsed
+		}
+		if song.Duration > 0 && listened > song.Duration {
+			listened = song.Duration
+		}
+
+		completion := 0.0
+		if song.Duration > 0 {
+			completion = float64(listened) * 100 / float64(song.Duration)
+		}
+
+		_, err = historyCollection.UpdateOne(ctx,
+			bson.M{"_id": playID},
+			bson.M{"$set": bson.M{
+				"duration":   listened,
+				"completion": completion,
+				"ended":      body.Ended,
+				"updated_at": time.Now(),
+			}},
+		)
+		if err != nil {
+			log.Println("❌ Failed to update play:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update play"})
+			return
+		}
+
+		// Songs shorter than the threshold count when played through
+		threshold := playThreshold()
+		if song.Duration > 0 && song.Duration < threshold {
+			threshold = song.Duration
+		}
+
+		counted := play.Counted
+		if !counted && listened >= threshold {
+			// Flipping counted first means two racing updates can't both count the play
+			result, err := historyCollection.UpdateOne(ctx,
+				bson.M{"_id": playID, "counted": false},
+				bson.M{"$set": bson.M{"counted": true}},
+			)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count play"})
+				return
+			}
+
+			if result.ModifiedCount > 0 {
+				_, err := songcollection.UpdateOne(ctx,
+					bson.M{"song_id": play.SongID},
+					bson.M{"$inc": bson.M{
+						"play_count":                 1,
+						"user_play_counts." + userID: 1,
+					}},
+				)
+				if err != nil {
+					log.Printf("⚠️ Failed to increment play count of song %s: %v\n", play.SongID, err)
+				}
+			}
+			counted = true
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"play_id":          playID.Hex(),
+			"listened_seconds": listened,
+			"completion":       completion,
+			"counted":          counted,
+			"ended":            body.Ended,
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ishanbagra18/ecommerce-using-go/database"
+	"github.com/ishanbagra18/ecommerce-using-go/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const playbackQueueMax = 1000
+
+var playbackCollection *mongo.Collection
+
+func InitPlaybackController() {
+	playbackCollection = database.OpenCollection(database.Client, "playback_states")
+	log.Println("✔ Playback collection initialized")
+
+	database.CreateIndexes(playbackCollection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+	})
+}
+
+// loadPlaybackState returns the user's playback state, or a fresh one at
+// version 0 if they never played anything
+func loadPlaybackState(ctx context.Context, userID string) (models.PlaybackState, error) {
+	state := models.PlaybackState{UserID: userID, Repeat: models.RepeatOff}
+	err := playbackCollection.FindOne(ctx, bson.M{"user_id": userID}).Decode(&state)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return state, err
+	}
+	normalizePlaybackState(&state)
+	return state, nil
+}
+
+func normalizePlaybackState(state *models.PlaybackState) {
+	for _, list := range []*[]string{&state.UpNext, &state.Queue, &state.History, &state.SourceSongs} {
+		if *list == nil {
+			*list = []string{}
+		}
+	}
+}
+
+// visibleSongIDs keeps the song IDs the viewer may play, in order
+func visibleSongIDs(ctx context.Context, viewer songViewer, songIDs []string) ([]string, error) {
+	if len(songIDs) == 0 {
+		return []string{}, nil
+	}
+	cursor, err := songcollection.Find(ctx,
+		viewer.songFilter(bson.M{"song_id": bson.M{"$in": songIDs}}),
+		options.Find().SetProjection(bson.M{"song_id": 1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var songs []models.Song
+	if err := cursor.All(ctx, &songs); err != nil {
+		return nil, err
+	}
+	visible := make(map[string]bool, len(songs))
+	for _, song := range songs {
+		visible[song.SongID] = true
+	}
+
+	kept := make([]string, 0, len(songIDs))
+	for _, songID := range songIDs {
+		if visible[songID] {
+			kept = append(kept, songID)
+		}
+	}
+	return kept, nil
+}
+
+// GetPlaybackState returns where the user left off, with the current song and
+// where to resume it: a song still playing on another device has moved on
+// since it last reported its position
+//
+//	GET /playback
+func GetPlaybackState() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		state, err := loadPlaybackState(ctx, c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch playback state"})
+			return
+		}
+
+		var song *models.Song
+		resumeAt := state.Position
+		if state.SongID != nil {
+			var current models.Song
+			err := songcollection.FindOne(ctx, publicSongFilter(c, bson.M{"song_id": *state.SongID}),
+				options.FindOne().SetProjection(bson.M{"waveform": 0, "user_play_counts": 0}),
+			).Decode(&current)
+			if err == nil {
+				song = &current
+				if state.IsPlaying {
+					resumeAt += time.Since(state.UpdatedAt).Seconds()
+				}
+				if current.Duration > 0 && resumeAt > float64(current.Duration) {
+					resumeAt = float64(current.Duration)
+				}
+			} else if err != mongo.ErrNoDocuments {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch song"})
+				return
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{"state": state, "song": song, "resume_at": resumeAt})
+	}
+}
+
+// UpdatePlaybackState saves what the device is playing and where. The body
+// carries the version the device last saw; if another device has written
+// since, the write is refused with 409 and the current state so the device can
+// catch up. A queue sent here replaces the rest of the queue and becomes its
+// source; shuffle reorders it like POST /queue/shuffle. Both are optional and
+// the /queue endpoints are the finer-grained way to change them.
+//
+//	PUT /playback {"version": 3, "song_id": "...", "position": 42.5, "device_id": "...", "queue": ["..."], "shuffle": true}
+func UpdatePlaybackState() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("user_id")
+
+		var input struct {
+			Version    *int64            `json:"version" binding:"required"`
+			SongID     *string           `json:"song_id"`
+			Position   float64           `json:"position"`
+			IsPlaying  bool              `json:"is_playing"`
+			Queue      *[]string         `json:"queue"`
+			Shuffle    *bool             `json:"shuffle"`
+			Repeat     models.RepeatMode `json:"repeat"`
+			DeviceID   string            `json:"device_id" binding:"required"`
+			DeviceName string            `json:"device_name"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "version and device_id are required"})
+			return
+		}
+		if input.Repeat == "" {
+			input.Repeat = models.RepeatOff
+		}
+		if !models.IsValidRepeatMode(input.Repeat) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "repeat must be off, all or one"})
+			return
+		}
+		if input.Position < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "position can't be negative"})
+			return
+		}
+		if input.Queue != nil && len(*input.Queue) > playbackQueueMax {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "queue is too long", "max": playbackQueueMax})
+			return
+		}
+		if input.SongID != nil && *input.SongID == "" {
+			input.SongID = nil
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if input.SongID != nil {
+			var song models.Song
+			err := songcollection.FindOne(ctx, publicSongFilter(c, bson.M{"song_id": *input.SongID}),
+				options.FindOne().SetProjection(bson.M{"duration": 1}),
+			).Decode(&song)
+			if err != nil {
+				c.JSON(http.StatusNotFound, gin.H{"error": "song not found"})
+				return
+			}
+			if song.Duration > 0 && input.Position > float64(song.Duration) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "position is past the end of the song"})
+				return
+			}
+		}
+
+		update := bson.M{"$set": bson.M{
+			"song_id":     input.SongID,
+			"position":    input.Position,
+			"is_playing":  input.IsPlaying && input.SongID != nil,
+			"repeat":      input.Repeat,
+			"device_id":   input.DeviceID,
+			"device_name": input.DeviceName,
+			"version":     *input.Version + 1,
+			"updated_at":  time.Now(),
+		}}
+		respondConflict := func() {
+			current, err := loadPlaybackState(ctx, userID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch playback state"})
+				return
+			}
+			c.JSON(http.StatusConflict, gin.H{"error": "playback state was changed on another device", "state": current})
+		}
+
+		// The queue is worked out from the state the device saw; the version
+		// check on the write below makes sure it's still that state
+		queueSent := input.Queue != nil || input.Shuffle != nil
+		if queueSent {
+			current, err := loadPlaybackState(ctx, userID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch playback state"})
+				return
+			}
+			if current.Version != *input.Version {
+				respondConflict()
+				return
+			}
+			if input.Queue != nil {
+				queue, err := visibleSongIDs(ctx, viewerFromContext(c), *input.Queue)
+				if err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check queue"})
+					return
+				}
+				current.Source = nil
+				current.SourceSongs = queue
+				current.Queue = append([]string{}, queue...)
+				current.Shuffle = false
+				current.ShuffleSeed = 0
+			}
+			if input.Shuffle != nil && (*input.Shuffle != current.Shuffle || input.Queue != nil) {
+				setShuffle(&current, *input.Shuffle)
+			}
+
+			set := update["$set"].(bson.M)
+			set["queue"] = current.Queue
+			set["source_songs"] = current.SourceSongs
+			set["shuffle"] = current.Shuffle
+			set["shuffle_seed"] = current.ShuffleSeed
+			if input.Queue != nil {
+				update["$unset"] = bson.M{"source": ""}
+			}
+		}
+
+		// Version 0 means the device has never seen a state, so create one
+		if *input.Version == 0 {
+			insert := bson.M{
+				"up_next": []string{},
+				"history": []string{},
+			}
+			if !queueSent {
+				insert["queue"] = []string{}
+				insert["source_songs"] = []string{}
+				insert["shuffle"] = false
+				insert["shuffle_seed"] = 0
+			}
+			update["$setOnInsert"] = insert
+		}
+
+		var state models.PlaybackState
+		err := playbackCollection.FindOneAndUpdate(ctx,
+			bson.M{"user_id": userID, "version": *input.Version},
+			update,
+			options.FindOneAndUpdate().SetReturnDocument(options.After).SetUpsert(*input.Version == 0),
+		).Decode(&state)
+		if err == mongo.ErrNoDocuments || mongo.IsDuplicateKeyError(err) {
+			respondConflict()
+			return
+		}
+		if err != nil {
+			log.Println("❌ Failed to save playback state:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save playback state"})
+			return
+		}
+		normalizePlaybackState(&state)
+
+		c.JSON(http.StatusOK, gin.H{"state": state})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/ishanbagra18/ecommerce-using-go/database"
 	"github.com/ishanbagra18/ecommerce-using-go/models"	
@@ -21,9 +22,98 @@ var playlistCollection *mongo.Collection
 
 func InitPlaylistController() {
 	playlistCollection = database.OpenCollection(database.Client, "playlists")
+
+	database.CreateIndexes(playlistCollection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "song_ids", Value: 1}}},
+		{
+			Keys: bson.D{{Key: "owner_id", Value: 1}, {Key: "mix_key", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"mix_key": bson.M{"$exists": true}}),
+		},
+		{
+			Keys: bson.D{{Key: "name", Value: "text"}, {Key: "tags", Value: "text"}, {Key: "description", Value: "text"}},
+			Options: options.Index().
+				SetName("playlist_text").
+				SetDefaultLanguage("none").
+				SetWeights(bson.D{{Key: "name", Value: 10}, {Key: "tags", Value: 3}, {Key: "description", Value: 1}}),
+		},
+	})
 }
 
 
+// canReadPlaylist reports whether a user may open a playlist: public ones,
+// their own, and mixes generated for them
+func canReadPlaylist(playlist models.Playlist, userID string) bool {
+	if playlist.IsPublic {
+		return true
+	}
+	if userID == "" {
+		return false
+	}
+	if playlist.OwnerID != nil {
+		return *playlist.OwnerID == userID
+	}
+	return playlist.CreatorID == nil || *playlist.CreatorID == userID
+}
+
+// viewPlaylists adjusts the playlists' song lists for the viewer, leaving the
+// stored playlists untouched: explicit songs are taken out if the viewer hides
+// them, and songs not licensed where the viewer is stay listed but are named
+// in UnavailableSongIDs so apps can grey them out
+func viewPlaylists(ctx context.Context, viewer songViewer, playlists []models.Playlist) error {
+	var ids []string
+	for _, playlist := range playlists {
+		ids = append(ids, playlist.SongIDs...)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	restricted := bson.A{bson.M{"availability": bson.M{"$ne": nil}}}
+	if viewer.HideExplicit {
+		restricted = append(restricted, bson.M{"explicit": true})
+	}
+	cursor, err := songcollection.Find(ctx,
+		bson.M{"song_id": bson.M{"$in": ids}, "$or": restricted},
+		options.Find().SetProjection(bson.M{"song_id": 1, "explicit": 1, "availability": 1, "uploaded_by": 1}),
+	)
+	if err != nil {
+		return err
+	}
+	var songs []models.Song
+	if err := cursor.All(ctx, &songs); err != nil {
+		return err
+	}
+	if len(songs) == 0 {
+		return nil
+	}
+	hidden := make(map[string]bool)
+	unavailable := make(map[string]bool)
+	for _, song := range songs {
+		if viewer.HideExplicit && song.Explicit {
+			hidden[song.SongID] = true
+		} else if !viewer.availableTo(song) {
+			unavailable[song.SongID] = true
+		}
+	}
+
+	for i := range playlists {
+		kept := make([]string, 0, len(playlists[i].SongIDs))
+		for _, songID := range playlists[i].SongIDs {
+			if hidden[songID] {
+				continue
+			}
+			kept = append(kept, songID)
+			if unavailable[songID] && !containsString(playlists[i].UnavailableSongIDs, songID) {
+				playlists[i].UnavailableSongIDs = append(playlists[i].UnavailableSongIDs, songID)
+			}
+		}
+		playlists[i].SongIDs = kept
+	}
+	return nil
+}
+
 // -------------------- GET PLAYLIST BY ID --------------------
 func GetPlaylistByID() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -47,15 +137,18 @@ func GetPlaylistByID() gin.HandlerFunc {
 		}
 
 		// Check if playlist is public or belongs to user
-		userID, exists := c.Get("user_id")
-		if !playlist.IsPublic {
-			if !exists || (playlist.CreatorID != nil && *playlist.CreatorID != userID.(string)) {
-				c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
-				return
-			}
+		if !canReadPlaylist(playlist, c.GetString("user_id")) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+			return
+		}
+
+		playlists := []models.Playlist{playlist}
+		if err := viewPlaylists(context.TODO(), viewerFromContext(c), playlists); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching playlist"})
+			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{"playlist": playlist})
+		c.JSON(http.StatusOK, gin.H{"playlist": playlists[0]})
 	}
 }
 
@@ -221,6 +314,10 @@ func GetAllPlaylists() gin.HandlerFunc {
 		if playlists == nil {
 			playlists = []models.Playlist{}
 		}
+		if err := viewPlaylists(context.TODO(), viewerFromContext(c), playlists); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching playlists"})
+			return
+		}
 
 		c.JSON(http.StatusOK, gin.H{"playlists": playlists})
 	}
@@ -275,6 +372,10 @@ func GetMyPlaylists() gin.HandlerFunc {
 		if playlists == nil {
 			playlists = []models.Playlist{}
 		}
+		if err := viewPlaylists(context.TODO(), viewerFromContext(c), playlists); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching playlists"})
+			return
+		}
 
 		c.JSON(http.StatusOK, gin.H{
 			"playlists": playlists,
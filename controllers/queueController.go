@@ -0,0 +1,565 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ishanbagra18/ecommerce-using-go/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	queueHistoryMax = 100
+	queueArtistSize = 100 // songs queued when playing an artist, most played first
+	queueSongsShown = 50  // upcoming songs GetQueue returns in full
+)
+
+var (
+	errQueuePosition = errors.New("no queue item at that position")
+	errQueueFull     = errors.New("queue is full")
+	errQueueNoPrev   = errors.New("nothing was played before this song")
+	errPlaybackBusy  = errors.New("playback state keeps changing, try again")
+)
+
+// shuffledSongs orders songs by a seeded Fisher-Yates shuffle, so the same
+// songs and seed always give the same order
+func shuffledSongs(songIDs []string, seed int64) []string {
+	shuffled := append([]string{}, songIDs...)
+	rand.New(rand.NewSource(seed)).Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	return shuffled
+}
+
+// shuffledRemaining orders the songs left to play as shuffledSongs(source,
+// seed) orders them, so a device holding the source and the seed gets the same
+// order. Songs the source doesn't have keep their order at the end.
+func shuffledRemaining(remaining []string, source []string, seed int64) []string {
+	left := make(map[string]int, len(remaining))
+	for _, songID := range remaining {
+		left[songID]++
+	}
+	ordered := make([]string, 0, len(remaining))
+	for _, list := range [][]string{shuffledSongs(source, seed), remaining} {
+		for _, songID := range list {
+			if left[songID] > 0 {
+				ordered = append(ordered, songID)
+				left[songID]--
+			}
+		}
+	}
+	return ordered
+}
+
+// setShuffle turns shuffle on, reordering the rest of the source with a fresh
+// seed, or off, putting it back in the source's order
+func setShuffle(state *models.PlaybackState, shuffle bool) {
+	if shuffle {
+		state.ShuffleSeed = rand.Int63()
+		state.Queue = shuffledRemaining(state.Queue, state.SourceSongs, state.ShuffleSeed)
+	} else {
+		state.Queue = unshuffledSongs(state.Queue, state.SourceSongs)
+	}
+	state.Shuffle = shuffle
+}
+
+// unshuffledSongs puts songs back in the order their source lists them;
+// songs the source doesn't have keep their order at the end
+func unshuffledSongs(songIDs []string, source []string) []string {
+	order := make(map[string]int, len(source))
+	for i, songID := range source {
+		if _, ok := order[songID]; !ok {
+			order[songID] = i
+		}
+	}
+	rank := func(songID string) int {
+		if i, ok := order[songID]; ok {
+			return i
+		}
+		return len(source)
+	}
+
+	sorted := append([]string{}, songIDs...)
+	sort.SliceStable(sorted, func(i, j int) bool { return rank(sorted[i]) < rank(sorted[j]) })
+	return sorted
+}
+
+// mutatePlayback applies change to the user's playback state and saves it,
+// starting over from the fresh state if another device wrote in between
+func mutatePlayback(ctx context.Context, userID string, change func(*models.PlaybackState) error) (models.PlaybackState, error) {
+	for attempt := 0; attempt < 3; attempt++ {
+		state, err := loadPlaybackState(ctx, userID)
+		if err != nil {
+			return state, err
+		}
+		version := state.Version
+		if err := change(&state); err != nil {
+			return state, err
+		}
+		state.Version = version + 1
+		state.UpdatedAt = time.Now()
+
+		if version == 0 {
+			_, err = playbackCollection.InsertOne(ctx, state)
+			if mongo.IsDuplicateKeyError(err) {
+				continue
+			}
+			return state, err
+		}
+		result, err := playbackCollection.ReplaceOne(ctx, bson.M{"user_id": userID, "version": version}, state)
+		if err == nil && result.MatchedCount == 0 {
+			continue
+		}
+		return state, err
+	}
+	return models.PlaybackState{}, errPlaybackBusy
+}
+
+// startSong makes songID the current song, moving the one playing to history
+func startSong(state *models.PlaybackState, songID *string) {
+	if state.SongID != nil {
+		state.History = append(state.History, *state.SongID)
+		if len(state.History) > queueHistoryMax {
+			state.History = state.History[len(state.History)-queueHistoryMax:]
+		}
+	}
+	state.SongID = songID
+	state.Position = 0
+	state.IsPlaying = songID != nil
+}
+
+// respondQueue answers a queue change with the new state or the right error
+func respondQueue(c *gin.Context, state models.PlaybackState, err error) {
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, gin.H{"state": state})
+	case errors.Is(err, errQueuePosition), errors.Is(err, errQueueFull):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, errQueueNoPrev), errors.Is(err, errPlaybackBusy):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Println("❌ Failed to update queue:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update queue"})
+	}
+}
+
+// queueSection picks up_next or queue out of the state
+func queueSection(state *models.PlaybackState, section string) *[]string {
+	if section == "up_next" {
+		return &state.UpNext
+	}
+	return &state.Queue
+}
+
+// GetQueue returns the current song, what plays next and what played before,
+// with the songs themselves for the current song and the next few
+//
+//	GET /queue
+func GetQueue() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		state, err := loadPlaybackState(ctx, c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch queue"})
+			return
+		}
+
+		ids := append([]string{}, state.UpNext...)
+		ids = append(ids, state.Queue...)
+		if len(ids) > queueSongsShown {
+			ids = ids[:queueSongsShown]
+		}
+		if state.SongID != nil {
+			ids = append(ids, *state.SongID)
+		}
+
+		songs := []models.Song{}
+		if len(ids) > 0 {
+			cursor, err := songcollection.Find(ctx,
+				publicSongFilter(c, bson.M{"song_id": bson.M{"$in": ids}}),
+				options.Find().SetProjection(bson.M{"waveform": 0, "user_play_counts": 0}),
+			)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch songs"})
+				return
+			}
+			if err := cursor.All(ctx, &songs); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse songs"})
+				return
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{"state": state, "songs": songs})
+	}
+}
+
+// sourceSongs lists the songs of a playlist, album or artist the user may
+// play, in the source's own order
+func sourceSongs(ctx context.Context, c *gin.Context, sourceType models.QueueSourceType, sourceID string) (*models.QueueSource, []string, bool) {
+	viewer := viewerFromContext(c)
+	source := &models.QueueSource{Type: sourceType, ID: sourceID}
+
+	var songIDs []string
+	switch sourceType {
+	case models.QueueSourcePlaylist:
+		playlist, ok := visiblePlaylist(ctx, c, sourceID)
+		if !ok {
+			return nil, nil, false
+		}
+		source.Name = *playlist.Name
+		songIDs = playlist.SongIDs
+
+	case models.QueueSourceAlbum:
+		var album models.Album
+		if err := albumCollection.FindOne(ctx, bson.M{"album_id": sourceID}).Decode(&album); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Album not found"})
+			return nil, nil, false
+		}
+		source.Name = *album.Title
+		songIDs = albumSongIDs(album)
+
+	case models.QueueSourceArtist:
+		var artist models.Artist
+		if err := artistCollection.FindOne(ctx, bson.M{"artist_id": sourceID}).Decode(&artist); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Artist not found"})
+			return nil, nil, false
+		}
+		source.Name = *artist.Name
+
+		cursor, err := songcollection.Find(ctx,
+			viewer.songFilter(bson.M{"artist_ids": sourceID}),
+			options.Find().
+				SetSort(bson.D{{Key: "play_count", Value: -1}, {Key: "_id", Value: 1}}).
+				SetLimit(queueArtistSize).
+				SetProjection(bson.M{"song_id": 1}),
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch songs"})
+			return nil, nil, false
+		}
+		var songs []models.Song
+		if err := cursor.All(ctx, &songs); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse songs"})
+			return nil, nil, false
+		}
+		for _, song := range songs {
+			songIDs = append(songIDs, song.SongID)
+		}
+		return source, songIDs, true
+	}
+
+	visible, err := visibleSongIDs(ctx, viewer, songIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch songs"})
+		return nil, nil, false
+	}
+	if len(visible) > playbackQueueMax {
+		visible = visible[:playbackQueueMax]
+	}
+	return source, visible, true
+}
+
+// PlayQueueSource replaces the queue with a playlist, album or artist and
+// starts playing it, from start_song_id if given. With shuffle the order
+// comes from a fresh seed, returned so every device shows the same order.
+// Songs queued by hand stay up next.
+//
+//	POST /queue/play {"source_type": "album", "source_id": "...", "shuffle": true}
+func PlayQueueSource() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body struct {
+			SourceType  models.QueueSourceType `json:"source_type" binding:"required"`
+			SourceID    string                 `json:"source_id" binding:"required"`
+			Shuffle     bool                   `json:"shuffle"`
+			StartSongID string                 `json:"start_song_id"`
+		}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "source_type and source_id are required"})
+			return
+		}
+		if !models.IsValidQueueSourceType(body.SourceType) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "source_type must be playlist, album or artist"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		source, songIDs, ok := sourceSongs(ctx, c, body.SourceType, body.SourceID)
+		if !ok {
+			return
+		}
+		if len(songIDs) == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "nothing to play"})
+			return
+		}
+		start := -1
+		if body.StartSongID != "" {
+			for i, songID := range songIDs {
+				if songID == body.StartSongID {
+					start = i
+					break
+				}
+			}
+			if start == -1 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "start_song_id isn't in this " + string(body.SourceType)})
+				return
+			}
+		}
+
+		state, err := mutatePlayback(ctx, c.GetString("user_id"), func(state *models.PlaybackState) error {
+			state.Source = source
+			state.SourceSongs = songIDs
+			state.Shuffle = body.Shuffle
+			state.ShuffleSeed = 0
+
+			var first string
+			switch {
+			case body.Shuffle:
+				// The start song plays first; everything else is shuffled
+				state.ShuffleSeed = rand.Int63()
+				if start < 0 {
+					start = rand.Intn(len(songIDs))
+				}
+				first = songIDs[start]
+				rest := append(append([]string{}, songIDs[:start]...), songIDs[start+1:]...)
+				state.Queue = shuffledRemaining(rest, songIDs, state.ShuffleSeed)
+			case start >= 0:
+				first = songIDs[start]
+				state.Queue = append([]string{}, songIDs[start+1:]...)
+			default:
+				first = songIDs[0]
+				state.Queue = append([]string{}, songIDs[1:]...)
+			}
+
+			startSong(state, &first)
+			return nil
+		})
+		respondQueue(c, state, err)
+	}
+}
+
+// AddToQueue queues a song by hand, after the other hand-queued songs or,
+// with next, before them
+//
+//	POST /queue/add {"song_id": "...", "next": true}
+func AddToQueue() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body struct {
+			SongID string `json:"song_id" binding:"required"`
+			Next   bool   `json:"next"`
+		}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "song_id is required"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		count, err := songcollection.CountDocuments(ctx, publicSongFilter(c, bson.M{"song_id": body.SongID}))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch song"})
+			return
+		}
+		if count == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "song not found"})
+			return
+		}
+
+		state, err := mutatePlayback(ctx, c.GetString("user_id"), func(state *models.PlaybackState) error {
+			if len(state.UpNext)+len(state.Queue) >= playbackQueueMax {
+				return errQueueFull
+			}
+			if body.Next {
+				state.UpNext = append([]string{body.SongID}, state.UpNext...)
+			} else {
+				state.UpNext = append(state.UpNext, body.SongID)
+			}
+			return nil
+		})
+		respondQueue(c, state, err)
+	}
+}
+
+// MoveQueueItem moves a song within up_next or within queue
+//
+//	POST /queue/move {"section": "up_next", "from": 3, "to": 0}
+func MoveQueueItem() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body struct {
+			Section string `json:"section" binding:"required,oneof=up_next queue"`
+			From    *int   `json:"from" binding:"required"`
+			To      *int   `json:"to" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "section (up_next or queue), from and to are required"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		state, err := mutatePlayback(ctx, c.GetString("user_id"), func(state *models.PlaybackState) error {
+			list := queueSection(state, body.Section)
+			from, to := *body.From, *body.To
+			if from < 0 || from >= len(*list) || to < 0 || to >= len(*list) {
+				return errQueuePosition
+			}
+			songID := (*list)[from]
+			rest := append(append([]string{}, (*list)[:from]...), (*list)[from+1:]...)
+			*list = append(append(append([]string{}, rest[:to]...), songID), rest[to:]...)
+			return nil
+		})
+		respondQueue(c, state, err)
+	}
+}
+
+// RemoveQueueItem takes a song out of up_next or queue
+//
+//	POST /queue/remove {"section": "queue", "index": 4}
+func RemoveQueueItem() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body struct {
+			Section string `json:"section" binding:"required,oneof=up_next queue"`
+			Index   *int   `json:"index" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "section (up_next or queue) and index are required"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		state, err := mutatePlayback(ctx, c.GetString("user_id"), func(state *models.PlaybackState) error {
+			list := queueSection(state, body.Section)
+			index := *body.Index
+			if index < 0 || index >= len(*list) {
+				return errQueuePosition
+			}
+			*list = append(append([]string{}, (*list)[:index]...), (*list)[index+1:]...)
+			return nil
+		})
+		respondQueue(c, state, err)
+	}
+}
+
+// ClearQueue empties everything after the current song
+//
+//	DELETE /queue
+func ClearQueue() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		state, err := mutatePlayback(ctx, c.GetString("user_id"), func(state *models.PlaybackState) error {
+			state.UpNext = []string{}
+			state.Queue = []string{}
+			state.Source = nil
+			state.SourceSongs = []string{}
+			return nil
+		})
+		respondQueue(c, state, err)
+	}
+}
+
+// NextTrack skips to the next song: hand-queued songs first, then the rest of
+// the source. With repeat all, a finished source starts over, reshuffled with
+// the next seed. Playback stops when nothing is left.
+//
+//	POST /queue/next
+func NextTrack() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		state, err := mutatePlayback(ctx, c.GetString("user_id"), func(state *models.PlaybackState) error {
+			if len(state.UpNext) == 0 && len(state.Queue) == 0 && state.Repeat == models.RepeatAll && len(state.SourceSongs) > 0 {
+				state.Queue = append([]string{}, state.SourceSongs...)
+				if state.Shuffle {
+					state.ShuffleSeed++
+					state.Queue = shuffledSongs(state.SourceSongs, state.ShuffleSeed)
+				}
+			}
+
+			var next string
+			switch {
+			case len(state.UpNext) > 0:
+				next, state.UpNext = state.UpNext[0], state.UpNext[1:]
+			case len(state.Queue) > 0:
+				next, state.Queue = state.Queue[0], state.Queue[1:]
+			default:
+				startSong(state, nil)
+				return nil
+			}
+			startSong(state, &next)
+			return nil
+		})
+		respondQueue(c, state, err)
+	}
+}
+
+// PreviousTrack goes back to the last song played; the current one goes back
+// to the front of the queue
+//
+//	POST /queue/previous
+func PreviousTrack() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		state, err := mutatePlayback(ctx, c.GetString("user_id"), func(state *models.PlaybackState) error {
+			if len(state.History) == 0 {
+				return errQueueNoPrev
+			}
+			previous := state.History[len(state.History)-1]
+			state.History = state.History[:len(state.History)-1]
+			if state.SongID != nil {
+				state.Queue = append([]string{*state.SongID}, state.Queue...)
+			}
+			state.SongID = &previous
+			state.Position = 0
+			state.IsPlaying = true
+			return nil
+		})
+		respondQueue(c, state, err)
+	}
+}
+
+// SetShuffle turns shuffle on, reordering the rest of the source with a fresh
+// seed, or off, putting it back in the source's order. Hand-queued songs keep
+// their order either way.
+//
+//	POST /queue/shuffle {"shuffle": true}
+func SetShuffle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body struct {
+			Shuffle *bool `json:"shuffle" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "shuffle is required"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		state, err := mutatePlayback(ctx, c.GetString("user_id"), func(state *models.PlaybackState) error {
+			setShuffle(state, *body.Shuffle)
+			return nil
+		})
+		respondQueue(c, state, err)
+	}
+}
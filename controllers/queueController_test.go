@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestShuffledSongsSameSeedSameOrder(t *testing.T) {
+	source := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+
+	first := shuffledSongs(source, 42)
+	second := shuffledSongs(append([]string{}, source...), 42)
+	if !reflect.DeepEqual(first, second) {
+		t.Fatalf("same seed gave %v and %v", first, second)
+	}
+
+	sorted := append([]string{}, first...)
+	sort.Strings(sorted)
+	if !reflect.DeepEqual(sorted, source) {
+		t.Errorf("shuffledSongs(%v) = %v, not a permutation", source, first)
+	}
+	if !reflect.DeepEqual(source, []string{"a", "b", "c", "d", "e", "f", "g", "h"}) {
+		t.Errorf("shuffledSongs changed its input: %v", source)
+	}
+}
+
+func TestShuffledRemaining(t *testing.T) {
+	source := []string{"a", "b", "c", "d", "e", "f"}
+	const seed = 7
+	full := shuffledSongs(source, seed)
+
+	tests := []struct {
+		name      string
+		remaining []string
+		want      []string
+	}{
+		{"whole source", source, full},
+		{"played songs left out", without(source, full[0], full[1]), full[2:]},
+		{"songs outside the source go last in their order", append([]string{"x"}, append(source, "y")...), append(append([]string{}, full...), "x", "y")},
+		{"repeats are kept", []string{"a", "a"}, []string{"a", "a"}},
+		{"nothing left", nil, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := shuffledRemaining(tt.remaining, source, seed)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("shuffledRemaining(%v) = %v, want %v", tt.remaining, got, tt.want)
+			}
+			// Another device with the same source and seed agrees
+			if again := shuffledRemaining(append([]string{}, tt.remaining...), source, seed); !reflect.DeepEqual(again, got) {
+				t.Errorf("second device got %v, first got %v", again, got)
+			}
+		})
+	}
+}
+
+func TestUnshuffledSongs(t *testing.T) {
+	source := []string{"a", "b", "c", "d"}
+
+	tests := []struct {
+		name  string
+		songs []string
+		want  []string
+	}{
+		{"back in source order", []string{"d", "b", "a", "c"}, []string{"a", "b", "c", "d"}},
+		{"played songs stay out", []string{"d", "b"}, []string{"b", "d"}},
+		{"songs outside the source keep their order at the end", []string{"y", "c", "x", "a"}, []string{"a", "c", "y", "x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unshuffledSongs(tt.songs, source); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("unshuffledSongs(%v) = %v, want %v", tt.songs, got, tt.want)
+			}
+		})
+	}
+}
+
+func without(songIDs []string, drop ...string) []string {
+	var kept []string
+	for _, songID := range songIDs {
+		if !containsString(drop, songID) {
+			kept = append(kept, songID)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,653 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"math"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ishanbagra18/ecommerce-using-go/database"
+	"github.com/ishanbagra18/ecommerce-using-go/helpers"
+	"github.com/ishanbagra18/ecommerce-using-go/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	radioArtistSpacing = 4              // an artist can't come back within this many tracks
+	radioRecentWindow  = 48 * time.Hour // songs the user played this recently are skipped
+	radioPlayedMemory  = 500
+	radioPoolSize      = 300
+	radioStationTTL    = 30 * 24 * time.Hour
+)
+
+var radioCollection *mongo.Collection
+
+func InitRadioController() {
+	radioCollection = database.OpenCollection(database.Client, "radio_stations")
+	log.Println("✔ Radio collection initialized")
+
+	database.CreateIndexes(radioCollection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "station_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}},
+		{Keys: bson.D{{Key: "updated_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(int32(radioStationTTL.Seconds()))},
+	})
+}
+
+// findStation loads a station owned by the logged-in user
+func findStation(ctx context.Context, c *gin.Context) (models.RadioStation, bool) {
+	var station models.RadioStation
+	err := radioCollection.FindOne(ctx, bson.M{"station_id": c.Param("station_id"), "user_id": c.GetString("user_id")}).Decode(&station)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, gin.H{"error": "station not found"})
+			return station, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch station"})
+		return station, false
+	}
+	return station, true
+}
+
+// CreateRadioStation starts a station from a song, artist, genre or mood, or playlist
+//
+//	POST /radio {"seed_type": "artist", "seed_id": "..."}
+func CreateRadioStation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("user_id")
+
+		var body struct {
+			SeedType models.RadioSeedType `json:"seed_type" binding:"required"`
+			SeedID   string               `json:"seed_id" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "seed_type and seed_id are required"})
+			return
+		}
+		if !models.IsValidRadioSeedType(body.SeedType) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "seed_type must be song, artist, genre or playlist"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		seedID := body.SeedID
+		var name string
+		switch body.SeedType {
+		case models.RadioSeedSong:
+			var song models.Song
+			err := songcollection.FindOne(ctx, bson.M{"song_id": seedID}).Decode(&song)
+			if err != nil || !viewerFromContext(c).canView(song) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "song not found"})
+				return
+			}
+			if song.Title != nil {
+				name = *song.Title
+			}
+
+		case models.RadioSeedArtist:
+			var artist models.Artist
+			if err := artistCollection.FindOne(ctx, bson.M{"artist_id": seedID}).Decode(&artist); err != nil {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Artist not found"})
+				return
+			}
+			name = *artist.Name
+
+		case models.RadioSeedGenre:
+			genre, err := findGenreOrMood(ctx, seedID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch genre"})
+				return
+			}
+			if genre == nil {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Genre not found"})
+				return
+			}
+			seedID = genre.Slug
+			name = genre.Name
+
+		case models.RadioSeedPlaylist:
+			playlist, ok := visiblePlaylist(ctx, c, seedID)
+			if !ok {
+				return
+			}
+			name = *playlist.Name
+		}
+
+		now := time.Now()
+		station := models.RadioStation{
+			ID:             primitive.NewObjectID(),
+			UserID:         userID,
+			SeedType:       body.SeedType,
+			SeedID:         seedID,
+			Name:           name + " Radio",
+			Played:         []string{},
+			RecentArtists:  [][]string{},
+			Liked:          []string{},
+			Disliked:       []string{},
+			ArtistFeedback: map[string]int{},
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+		station.StationID = station.ID.Hex()
+
+		if _, err := radioCollection.InsertOne(ctx, station); err != nil {
+			log.Println("❌ Failed to create station:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create station"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{"station": station})
+	}
+}
+
+// visiblePlaylist loads a playlist the user may read
+func visiblePlaylist(ctx context.Context, c *gin.Context, playlistID string) (models.Playlist, bool) {
+	var playlist models.Playlist
+	objID, err := primitive.ObjectIDFromHex(playlistID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid playlist ID"})
+		return playlist, false
+	}
+	if err := playlistCollection.FindOne(ctx, bson.M{"_id": objID}).Decode(&playlist); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Playlist not found"})
+		return playlist, false
+	}
+	if !canReadPlaylist(playlist, c.GetString("user_id")) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return playlist, false
+	}
+	return playlist, true
+}
+
+// findGenreOrMood finds a genre, or failing that a mood, by name, slug or alias
+func findGenreOrMood(ctx context.Context, name string) (*models.Genre, error) {
+	genre, err := findGenre(ctx, name, models.GenreKindGenre)
+	if err == nil && genre == nil {
+		genre, err = findGenre(ctx, name, models.GenreKindMood)
+	}
+	return genre, err
+}
+
+// radioSeeds returns the songs a station grows from, weighted, and a filter
+// for songs in the same style to fall back on
+func radioSeeds(ctx context.Context, station models.RadioStation) (map[string]float64, bson.M, error) {
+	seeds := make(map[string]float64)
+
+	var style bson.M
+	switch station.SeedType {
+	case models.RadioSeedSong:
+		seeds[station.SeedID] = 2
+		filter, err := tasteFilter(ctx, []string{station.SeedID}, seeds)
+		if err != nil {
+			return nil, nil, err
+		}
+		style = filter
+
+	case models.RadioSeedArtist:
+		cursor, err := songcollection.Find(ctx, bson.M{"artist_ids": station.SeedID},
+			options.Find().
+				SetSort(bson.D{{Key: "play_count", Value: -1}}).
+				SetLimit(20).
+				SetProjection(bson.M{"song_id": 1}),
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		var songs []models.Song
+		if err := cursor.All(ctx, &songs); err != nil {
+			return nil, nil, err
+		}
+		for _, song := range songs {
+			seeds[song.SongID] = 1
+		}
+
+		artistIDs := []string{station.SeedID}
+		var related models.RelatedArtists
+		err = relatedArtistCollection.FindOne(ctx, bson.M{"artist_id": station.SeedID}).Decode(&related)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return nil, nil, err
+		}
+		for i, r := range related.Related {
+			if i == 10 {
+				break
+			}
+			artistIDs = append(artistIDs, r.ArtistID)
+		}
+		style = bson.M{"artist_ids": bson.M{"$in": artistIDs}}
+
+	case models.RadioSeedGenre:
+		// Looked up by alias too, so a station outlives its genre being merged
+		genre, err := findGenreOrMood(ctx, station.SeedID)
+		if err != nil {
+			return nil, nil, err
+		}
+		switch {
+		case genre == nil:
+			// The genre is gone; the station falls back to popular songs
+		case genre.Kind == models.GenreKindMood:
+			style = bson.M{"moods": genre.Slug}
+		default:
+			slugs, err := genreSlugsWithDescendants(ctx, []string{genre.Slug})
+			if err != nil {
+				return nil, nil, err
+			}
+			style = bson.M{"genre_slug": bson.M{"$in": slugs}}
+		}
+
+	case models.RadioSeedPlaylist:
+		objID, _ := primitive.ObjectIDFromHex(station.SeedID)
+		var playlist models.Playlist
+		if err := playlistCollection.FindOne(ctx, bson.M{"_id": objID}).Decode(&playlist); err != nil && err != mongo.ErrNoDocuments {
+			return nil, nil, err
+		}
+		ids := playlist.SongIDs
+		if len(ids) > 50 {
+			ids = ids[:50]
+		}
+		for _, songID := range ids {
+			seeds[songID] = 1
+		}
+		filter, err := tasteFilter(ctx, ids, seeds)
+		if err != nil {
+			return nil, nil, err
+		}
+		style = filter
+	}
+
+	// Thumbs up steer the station towards more like them
+	for _, songID := range station.Liked {
+		seeds[songID] += 1.5
+	}
+
+	return seeds, style, nil
+}
+
+// songArtistKeys identifies a song's performers for artist spacing and
+// feedback. Songs not linked to artist profiles fall back to their artist line,
+// slugified because the keys are stored as field names in artist_feedback and
+// "." or "$" in them would nest the document.
+func songArtistKeys(song models.Song) []string {
+	var keys []string
+	for _, credit := range song.ArtistCredits {
+		if credit.Role == models.ArtistRolePrimary || credit.Role == models.ArtistRoleFeatured {
+			keys = append(keys, credit.ArtistID)
+		}
+	}
+	if len(keys) == 0 && song.Artist != nil {
+		if slug := helpers.Slugify(*song.Artist); slug != "" {
+			keys = append(keys, "name:"+slug)
+		}
+	}
+	return keys
+}
+
+// nextRadioTracks picks the next batch for a station: songs played alongside
+// its seeds, then songs in the same style, then popular songs, skipping what
+// the station already played, what the user heard recently and anything
+// disliked, and keeping radioArtistSpacing tracks between plays of one artist.
+func nextRadioTracks(ctx context.Context, c *gin.Context, station models.RadioStation, count int) ([]models.Song, [][]string, error) {
+	exclude := make(map[string]bool)
+	for _, songID := range station.Played {
+		exclude[songID] = true
+	}
+	for _, songID := range station.Disliked {
+		exclude[songID] = true
+	}
+	recent, err := historyCollection.Distinct(ctx, "song_id", bson.M{
+		"user_id":   station.UserID,
+		"played_at": bson.M{"$gte": time.Now().Add(-radioRecentWindow)},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, songID := range recent {
+		if id, ok := songID.(string); ok {
+			exclude[id] = true
+		}
+	}
+
+	seeds, style, err := radioSeeds(ctx, station)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// A song station opens with its seed
+	var picks []models.Song
+	if station.SeedType == models.RadioSeedSong && len(station.Played) == 0 {
+		var seed models.Song
+		if err := songcollection.FindOne(ctx, publicSongFilter(c, bson.M{"song_id": station.SeedID})).Decode(&seed); err == nil {
+			picks = append(picks, seed)
+		}
+	}
+	exclude[station.SeedID] = true
+
+	// 1️⃣ Candidates with scores
+	scores := make(map[string]float64)
+	if len(seeds) > 0 {
+		seedIDs := make([]string, 0, len(seeds))
+		for songID := range seeds {
+			seedIDs = append(seedIDs, songID)
+		}
+		cursor, err := similarityCollection.Find(ctx, bson.M{"song_id": bson.M{"$in": seedIDs}})
+		if err != nil {
+			return nil, nil, err
+		}
+		var similarities []models.SongSimilarity
+		if err := cursor.All(ctx, &similarities); err != nil {
+			return nil, nil, err
+		}
+		for _, s := range similarities {
+			for _, neighbour := range s.Similar {
+				if !exclude[neighbour.SongID] {
+					scores[neighbour.SongID] += seeds[s.SongID] * neighbour.Score
+				}
+			}
+		}
+	}
+
+	excluded := make([]string, 0, len(exclude))
+	for songID := range exclude {
+		excluded = append(excluded, songID)
+	}
+	addPopular := func(filter bson.M, base float64) error {
+		cursor, err := songcollection.Find(ctx,
+			publicSongFilter(c, bson.M{"$and": bson.A{filter, bson.M{"song_id": bson.M{"$nin": excluded}}}}),
+			options.Find().
+				SetSort(bson.D{{Key: "play_count", Value: -1}}).
+				SetLimit(radioPoolSize).
+				SetProjection(bson.M{"song_id": 1, "play_count": 1}),
+		)
+		if err != nil {
+			return err
+		}
+		var songs []models.Song
+		if err := cursor.All(ctx, &songs); err != nil {
+			return err
+		}
+		for _, song := range songs {
+			scores[song.SongID] += base * (1 + math.Log1p(float64(song.PlayCount))/10)
+		}
+		return nil
+	}
+	if style != nil {
+		if err := addPopular(style, 0.05); err != nil {
+			return nil, nil, err
+		}
+	}
+	// Never run dry
+	if len(scores) < count*3 {
+		if err := addPopular(bson.M{}, 0.01); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// 2️⃣ Load, adjust for feedback and shuffle a little so stations don't repeat each other
+	cursor, err := songcollection.Find(ctx,
+		publicSongFilter(c, bson.M{"song_id": bson.M{"$in": topKeys(scores, radioPoolSize)}}),
+		options.Find().SetProjection(bson.M{"waveform": 0, "user_play_counts": 0}),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	var candidates []models.Song
+	if err := cursor.All(ctx, &candidates); err != nil {
+		return nil, nil, err
+	}
+
+	ranked := make([]models.Song, 0, len(candidates))
+	adjusted := make(map[string]float64, len(candidates))
+	for _, song := range candidates {
+		feedback := 0
+		for _, key := range songArtistKeys(song) {
+			feedback += station.ArtistFeedback[key]
+		}
+		if feedback <= -2 {
+			continue
+		}
+		if feedback > 3 {
+			feedback = 3
+		}
+		adjusted[song.SongID] = scores[song.SongID] * math.Pow(1.3, float64(feedback)) * (0.8 + 0.4*rand.Float64())
+		ranked = append(ranked, song)
+	}
+	sort.Slice(ranked, func(i, j int) bool { return adjusted[ranked[i].SongID] > adjusted[ranked[j].SongID] })
+
+	// 3️⃣ Pick with artist spacing
+	recentArtists := append([][]string{}, station.RecentArtists...)
+	for _, pick := range picks {
+		recentArtists = append(recentArtists, songArtistKeys(pick))
+	}
+	tooSoon := func(keys []string) bool {
+		window := recentArtists
+		if len(window) > radioArtistSpacing {
+			window = window[len(window)-radioArtistSpacing:]
+		}
+		for _, track := range window {
+			for _, key := range keys {
+				if containsString(track, key) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	for _, song := range ranked {
+		if len(picks) == count {
+			break
+		}
+		keys := songArtistKeys(song)
+		if tooSoon(keys) {
+			continue
+		}
+		picks = append(picks, song)
+		recentArtists = append(recentArtists, keys)
+	}
+	// A station stuck on one artist plays on rather than going silent
+	if len(picks) == 0 && len(ranked) > 0 {
+		picks = append(picks, ranked[0])
+		recentArtists = append(recentArtists, songArtistKeys(ranked[0]))
+	}
+
+	if len(recentArtists) > radioArtistSpacing {
+		recentArtists = recentArtists[len(recentArtists)-radioArtistSpacing:]
+	}
+	return picks, recentArtists, nil
+}
+
+// NextRadioTracks hands out the next tracks of a station and remembers them
+//
+//	POST /radio/:station_id/next?count=10
+func NextRadioTracks() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		count, err := strconv.Atoi(c.DefaultQuery("count", "10"))
+		if err != nil || count < 1 {
+			count = 10
+		}
+		if count > 50 {
+			count = 50
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		station, ok := findStation(ctx, c)
+		if !ok {
+			return
+		}
+
+		tracks, recentArtists, err := nextRadioTracks(ctx, c, station, count)
+		if err != nil {
+			log.Println("❌ Failed to pick radio tracks:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to pick tracks"})
+			return
+		}
+
+		played := station.Played
+		for _, track := range tracks {
+			played = append(played, track.SongID)
+		}
+		if len(played) > radioPlayedMemory {
+			played = played[len(played)-radioPlayedMemory:]
+		}
+
+		_, err = radioCollection.UpdateOne(ctx,
+			bson.M{"station_id": station.StationID},
+			bson.M{"$set": bson.M{"played": played, "recent_artists": recentArtists, "updated_at": time.Now()}},
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update station"})
+			return
+		}
+
+		if tracks == nil {
+			tracks = []models.Song{}
+		}
+		c.JSON(http.StatusOK, gin.H{"station_id": station.StationID, "tracks": tracks})
+	}
+}
+
+// RadioFeedback records a thumbs up or down on a track the station played.
+// Up makes the song a seed and favours its artists; down drops the song for
+// good and, after two downs, its artists too.
+//
+//	POST /radio/:station_id/feedback {"song_id": "...", "rating": "up"}
+func RadioFeedback() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body struct {
+			SongID string `json:"song_id" binding:"required"`
+			Rating string `json:"rating" binding:"required,oneof=up down"`
+		}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "song_id and rating (up or down) are required"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		station, ok := findStation(ctx, c)
+		if !ok {
+			return
+		}
+		if !containsString(station.Played, body.SongID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "this station hasn't played that song"})
+			return
+		}
+
+		up := body.Rating == "up"
+		if (up && containsString(station.Liked, body.SongID)) || (!up && containsString(station.Disliked, body.SongID)) {
+			c.JSON(http.StatusOK, gin.H{"message": "Feedback already recorded"})
+			return
+		}
+
+		var song models.Song
+		err := songcollection.FindOne(ctx, bson.M{"song_id": body.SongID},
+			options.FindOne().SetProjection(bson.M{"artist": 1, "artist_credits": 1}),
+		).Decode(&song)
+		if err != nil && err != mongo.ErrNoDocuments {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch song"})
+			return
+		}
+
+		step := 1
+		update := bson.M{
+			"$addToSet": bson.M{"liked": body.SongID},
+			"$pull":     bson.M{"disliked": body.SongID},
+		}
+		if !up {
+			step = -1
+			update = bson.M{
+				"$addToSet": bson.M{"disliked": body.SongID},
+				"$pull":     bson.M{"liked": body.SongID},
+			}
+		}
+		inc := bson.M{}
+		for _, key := range songArtistKeys(song) {
+			inc["artist_feedback."+key] = step
+		}
+		if len(inc) > 0 {
+			update["$inc"] = inc
+		}
+		update["$set"] = bson.M{"updated_at": time.Now()}
+
+		if _, err := radioCollection.UpdateOne(ctx, bson.M{"station_id": station.StationID}, update); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save feedback"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Feedback saved", "rating": body.Rating})
+	}
+}
+
+// GetMyRadioStations lists the user's stations, most recently played first
+//
+//	GET /radio
+func GetMyRadioStations() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		cursor, err := radioCollection.Find(ctx,
+			bson.M{"user_id": c.GetString("user_id")},
+			options.Find().SetSort(bson.D{{Key: "updated_at", Value: -1}}).SetLimit(50),
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch stations"})
+			return
+		}
+		stations := []models.RadioStation{}
+		if err := cursor.All(ctx, &stations); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse stations"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"stations": stations})
+	}
+}
+
+// GetRadioStation returns one of the user's stations
+//
+//	GET /radio/:station_id
+func GetRadioStation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		station, ok := findStation(ctx, c)
+		if !ok {
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"station": station})
+	}
+}
+
+// DeleteRadioStation removes one of the user's stations
+//
+//	DELETE /radio/:station_id
+func DeleteRadioStation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		result, err := radioCollection.DeleteOne(ctx, bson.M{"station_id": c.Param("station_id"), "user_id": c.GetString("user_id")})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete station"})
+			return
+		}
+		if result.DeletedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "station not found"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Station deleted"})
+	}
+}
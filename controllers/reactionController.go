@@ -0,0 +1,214 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ishanbagra18/ecommerce-using-go/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var reactionCollection *mongo.Collection
+
+// Time windows accepted by the top liked/saved lists
+var reactionWindows = map[string]time.Duration{
+	"week":  7 * 24 * time.Hour,
+	"month": 30 * 24 * time.Hour,
+}
+
+// reactionFields returns the user array and the counter a reaction is stored in
+func reactionFields(reaction models.ReactionType) (string, string) {
+	if reaction == models.ReactionSave {
+		return "saves", "save_count"
+	}
+	return "likes", "like_count"
+}
+
+// toggleSongReaction adds or removes a like/save and moves the counter with it
+// in the same update, so concurrent toggles can never drift the count.
+// It reports whether the reaction is on afterwards.
+func toggleSongReaction(ctx context.Context, songID string, userID string, reaction models.ReactionType) (bool, error) {
+	users, counter := reactionFields(reaction)
+
+	added, err := songcollection.UpdateOne(ctx,
+		bson.M{"song_id": songID, users: bson.M{"$ne": userID}},
+		bson.M{"$addToSet": bson.M{users: userID}, "$inc": bson.M{counter: 1}},
+	)
+	if err != nil {
+		return false, err
+	}
+	if added.MatchedCount > 0 {
+		_, err := reactionCollection.UpdateOne(ctx,
+			bson.M{"song_id": songID, "user_id": userID, "type": reaction},
+			bson.M{"$setOnInsert": bson.M{"created_at": time.Now()}},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			log.Printf("⚠️ Failed to record %s of song %s: %v\n", reaction, songID, err)
+		}
+		return true, nil
+	}
+
+	removed, err := songcollection.UpdateOne(ctx,
+		bson.M{"song_id": songID, users: userID},
+		bson.M{"$pull": bson.M{users: userID}, "$inc": bson.M{counter: -1}},
+	)
+	if err != nil {
+		return false, err
+	}
+	if removed.MatchedCount == 0 {
+		return false, mongo.ErrNoDocuments
+	}
+
+	if _, err := reactionCollection.DeleteOne(ctx, bson.M{"song_id": songID, "user_id": userID, "type": reaction}); err != nil {
+		log.Printf("⚠️ Failed to remove %s of song %s: %v\n", reaction, songID, err)
+	}
+	return false, nil
+}
+
+// recountReactions recomputes like_count and save_count from the arrays
+func recountReactions(ctx context.Context, filter bson.M) (*mongo.UpdateResult, error) {
+	return songcollection.UpdateMany(ctx, filter, mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"like_count": bson.M{"$size": bson.M{"$ifNull": bson.A{"$likes", bson.A{}}}},
+			"save_count": bson.M{"$size": bson.M{"$ifNull": bson.A{"$saves", bson.A{}}}},
+		}}},
+	})
+}
+
+// moveSongReactions hands the reactions of one song to another, keeping the
+// older timestamp when a user reacted to both
+func moveSongReactions(ctx context.Context, fromSongID string, toSongID string) error {
+	cursor, err := reactionCollection.Find(ctx, bson.M{"song_id": fromSongID})
+	if err != nil {
+		return err
+	}
+
+	var reactions []models.SongReaction
+	if err := cursor.All(ctx, &reactions); err != nil {
+		return err
+	}
+
+	for _, r := range reactions {
+		_, err := reactionCollection.UpdateOne(ctx,
+			bson.M{"song_id": toSongID, "user_id": r.UserID, "type": r.Type},
+			bson.M{"$min": bson.M{"created_at": r.CreatedAt}},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = reactionCollection.DeleteMany(ctx, bson.M{"song_id": fromSongID})
+	return err
+}
+
+// topReactedSongs returns the songs liked or saved the most, either of all time
+// (?window=all, the default) or within the last week or month
+func topReactedSongs(c *gin.Context, reaction models.ReactionType) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	const limit = 10
+	_, counter := reactionFields(reaction)
+
+	window := c.DefaultQuery("window", "all")
+	if window == "all" {
+		opts := options.Find().
+			SetSort(bson.D{{Key: counter, Value: -1}, {Key: "_id", Value: 1}}).
+			SetLimit(limit)
+
+		cursor, err := songcollection.Find(ctx, publicSongFilter(c, bson.M{counter: bson.M{"$gt": 0}}), opts)
+		if err != nil {
+			log.Println("❌ Failed to fetch songs:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch songs"})
+			return
+		}
+		defer cursor.Close(ctx)
+
+		var songs []models.Song
+		if err := cursor.All(ctx, &songs); err != nil {
+			log.Println("❌ Failed to parse songs:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse songs"})
+			return
+		}
+		if songs == nil {
+			songs = []models.Song{}
+		}
+
+		log.Printf("✅ Successfully fetched %d top songs by %s\n", len(songs), counter)
+		c.JSON(http.StatusOK, gin.H{"songs": songs, "window": window})
+		return
+	}
+
+	period, ok := reactionWindows[window]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid window, use one of: week | month | all"})
+		return
+	}
+
+	// Over-fetch a little so hidden songs don't leave the list short
+	cursor, err := reactionCollection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"type": reaction, "created_at": bson.M{"$gte": time.Now().Add(-period)}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$song_id", "count": bson.M{"$sum": 1}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: limit * 3}},
+	})
+	if err != nil {
+		log.Println("❌ Failed to rank reactions:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch songs"})
+		return
+	}
+
+	var ranked []struct {
+		SongID string `bson:"_id"`
+		Count  int    `bson:"count"`
+	}
+	if err := cursor.All(ctx, &ranked); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse songs"})
+		return
+	}
+
+	ids := make([]string, 0, len(ranked))
+	for _, r := range ranked {
+		ids = append(ids, r.SongID)
+	}
+
+	songCursor, err := songcollection.Find(ctx, publicSongFilter(c, bson.M{"song_id": bson.M{"$in": ids}}))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch songs"})
+		return
+	}
+
+	var found []models.Song
+	if err := songCursor.All(ctx, &found); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse songs"})
+		return
+	}
+
+	byID := make(map[string]models.Song, len(found))
+	for _, song := range found {
+		byID[song.SongID] = song
+	}
+
+	type windowedSong struct {
+		models.Song
+		WindowCount int `json:"window_count"`
+	}
+	songs := make([]windowedSong, 0, limit)
+	for _, r := range ranked {
+		if song, ok := byID[r.SongID]; ok && len(songs) < limit {
+			songs = append(songs, windowedSong{Song: song, WindowCount: r.Count})
+		}
+	}
+
+	log.Printf("✅ Successfully fetched %d top songs by %s this %s\n", len(songs), reaction, window)
+	c.JSON(http.StatusOK, gin.H{"songs": songs, "window": window})
+}
@@ -0,0 +1,599 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ishanbagra18/ecommerce-using-go/database"
+	"github.com/ishanbagra18/ecommerce-using-go/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	similarityLookback     = 90 * 24 * time.Hour
+	similarityMaxPerUser   = 200 // most recent distinct songs per listener
+	similarityMinListeners = 2   // pairs shared by fewer listeners are noise
+	similarityNeighbours   = 50
+	profileHalfLife        = 30 * 24 * time.Hour
+	profileSeeds           = 100
+)
+
+const (
+	reasonListenedTogether = "listened_together" // played by people who play what you play
+	reasonYourTaste        = "your_taste"        // same genre, language or artist as what you play
+	reasonPopular          = "popular"           // nothing to go on yet
+)
+
+var similarityCollection *mongo.Collection
+
+func InitRecommendationController() {
+	similarityCollection = database.OpenCollection(database.Client, "song_similarities")
+	log.Println("✔ Song similarity collection initialized")
+
+	database.CreateIndexes(similarityCollection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "song_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+	})
+}
+
+// StartRecommendationJob rebuilds the song-to-song similarities every 6 hours
+func StartRecommendationJob() {
+	startPeriodicJob("song-similarity", 6*time.Hour, 30*time.Minute, computeSongSimilarities)
+}
+
+// computeSongSimilarities is item-to-item collaborative filtering over play
+// history. Two songs are similar when the same listeners play both:
+// listeners(a and b) / sqrt(listeners(a) · listeners(b)).
+func computeSongSimilarities(ctx context.Context) error {
+	now := time.Now()
+
+	cursor, err := historyCollection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"counted": true, "played_at": bson.M{"$gte": now.Add(-similarityLookback)}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":       bson.M{"user": "$user_id", "song": "$song_id"},
+			"last_play": bson.M{"$max": "$played_at"},
+		}}},
+		{{Key: "$sort", Value: bson.M{"last_play": -1}}},
+		{{Key: "$group", Value: bson.M{"_id": "$_id.user", "songs": bson.M{"$push": "$_id.song"}}}},
+		{{Key: "$project", Value: bson.M{"songs": bson.M{"$slice": bson.A{"$songs", similarityMaxPerUser}}}}},
+	}, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	listeners := newCoOccurrence()
+	for cursor.Next(ctx) {
+		var user struct {
+			Songs []string `bson:"songs"`
+		}
+		if err := cursor.Decode(&user); err != nil {
+			return err
+		}
+		listeners.add(user.Songs)
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+
+	similar := listeners.neighbours(similarityMinListeners, similarityNeighbours)
+
+	writer := newComputedWriter(ctx, similarityCollection, now)
+	for songID, neighbours := range similar {
+		doc := models.SongSimilarity{SongID: songID, Similar: similarSongs(neighbours), ComputedAt: now}
+		if err := writer.replace(bson.M{"song_id": songID}, doc); err != nil {
+			return err
+		}
+	}
+	// Songs that no longer share listeners with anything drop out
+	if err := writer.finish(); err != nil {
+		return err
+	}
+
+	log.Printf("🧠 Song similarities rebuilt: %d songs from %d listened songs\n", len(similar), len(listeners.baskets))
+	return nil
+}
+
+// computedWriter upserts freshly computed documents in batches of 500 as they
+// are produced, then drops the ones the run didn't produce again
+type computedWriter struct {
+	ctx        context.Context
+	collection *mongo.Collection
+	computedAt time.Time
+	writes     []mongo.WriteModel
+}
+
+func newComputedWriter(ctx context.Context, collection *mongo.Collection, computedAt time.Time) *computedWriter {
+	return &computedWriter{ctx: ctx, collection: collection, computedAt: computedAt, writes: make([]mongo.WriteModel, 0, 500)}
+}
+
+func (w *computedWriter) replace(filter bson.M, doc interface{}) error {
+	w.writes = append(w.writes, mongo.NewReplaceOneModel().SetFilter(filter).SetReplacement(doc).SetUpsert(true))
+	if len(w.writes) == cap(w.writes) {
+		return w.flush()
+	}
+	return nil
+}
+
+func (w *computedWriter) flush() error {
+	if len(w.writes) == 0 {
+		return nil
+	}
+	_, err := w.collection.BulkWrite(w.ctx, w.writes, options.BulkWrite().SetOrdered(false))
+	w.writes = w.writes[:0]
+	return err
+}
+
+func (w *computedWriter) finish() error {
+	if err := w.flush(); err != nil {
+		return err
+	}
+	_, err := w.collection.DeleteMany(w.ctx, bson.M{"computed_at": bson.M{"$lt": w.computedAt}})
+	return err
+}
+
+// coOccurrence counts how often two items land in the same basket: a
+// listener's songs, a listening session, a follower's artists
+type coOccurrence struct {
+	baskets  map[string]int // item -> baskets containing it
+	together map[[2]string]int
+}
+
+type scoredID struct {
+	ID    string
+	Score float64
+}
+
+func newCoOccurrence() *coOccurrence {
+	return &coOccurrence{baskets: map[string]int{}, together: map[[2]string]int{}}
+}
+
+func (co *coOccurrence) add(items []string) {
+	unique := make([]string, 0, len(items))
+	for _, item := range items {
+		if !containsString(unique, item) {
+			unique = append(unique, item)
+		}
+	}
+	sort.Strings(unique)
+
+	for i, a := range unique {
+		co.baskets[a]++
+		for _, b := range unique[i+1:] {
+			co.together[[2]string{a, b}]++
+		}
+	}
+}
+
+// neighbours scores every pair seen together at least minShared times by
+// cosine similarity, together(a, b) / sqrt(baskets(a) · baskets(b)), and
+// keeps each item's topN, best first
+func (co *coOccurrence) neighbours(minShared int, topN int) map[string][]scoredID {
+	result := make(map[string][]scoredID)
+	for pair, shared := range co.together {
+		if shared < minShared {
+			continue
+		}
+		a, b := pair[0], pair[1]
+		score := float64(shared) / math.Sqrt(float64(co.baskets[a])*float64(co.baskets[b]))
+		result[a] = append(result[a], scoredID{ID: b, Score: score})
+		result[b] = append(result[b], scoredID{ID: a, Score: score})
+	}
+
+	for item, list := range result {
+		sortScored(list)
+		if len(list) > topN {
+			result[item] = list[:topN]
+		}
+	}
+	return result
+}
+
+func sortScored(list []scoredID) {
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Score != list[j].Score {
+			return list[i].Score > list[j].Score
+		}
+		return list[i].ID < list[j].ID
+	})
+}
+
+func similarSongs(list []scoredID) []models.SimilarSong {
+	songs := make([]models.SimilarSong, 0, len(list))
+	for _, item := range list {
+		songs = append(songs, models.SimilarSong{SongID: item.ID, Score: item.Score})
+	}
+	return songs
+}
+
+// listenerProfile is what a user is into: recent plays fading with age,
+// plus likes and saves
+type listenerProfile struct {
+	affinity map[string]float64 // song_id -> weight
+	liked    map[string]bool
+}
+
+func buildListenerProfile(ctx context.Context, userID string) (listenerProfile, error) {
+	profile := listenerProfile{affinity: map[string]float64{}, liked: map[string]bool{}}
+
+	cursor, err := historyCollection.Find(ctx,
+		bson.M{"user_id": userID, "counted": true},
+		options.Find().
+			SetSort(bson.D{{Key: "played_at", Value: -1}}).
+			SetLimit(500).
+			SetProjection(bson.M{"song_id": 1, "played_at": 1}),
+	)
+	if err != nil {
+		return profile, err
+	}
+	var plays []models.History
+	if err := cursor.All(ctx, &plays); err != nil {
+		return profile, err
+	}
+	for _, play := range plays {
+		age := time.Since(play.PlayedAt).Hours()
+		profile.affinity[play.SongID] += math.Exp(-math.Ln2 * age / profileHalfLife.Hours())
+	}
+
+	cursor, err = reactionCollection.Find(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return profile, err
+	}
+	var reactions []models.SongReaction
+	if err := cursor.All(ctx, &reactions); err != nil {
+		return profile, err
+	}
+	for _, reaction := range reactions {
+		switch reaction.Type {
+		case models.ReactionLike:
+			profile.liked[reaction.SongID] = true
+			profile.affinity[reaction.SongID] += 3
+		case models.ReactionSave:
+			profile.affinity[reaction.SongID] += 2
+		}
+	}
+
+	return profile, nil
+}
+
+// topKeys returns the n keys with the highest values
+func topKeys(values map[string]float64, n int) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if values[keys[i]] != values[keys[j]] {
+			return values[keys[i]] > values[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	if len(keys) > n {
+		keys = keys[:n]
+	}
+	return keys
+}
+
+type recommendedSong struct {
+	models.Song
+	Score     float64 `json:"score"`
+	Reason    string  `json:"reason"`
+	BecauseOf *string `json:"because_of,omitempty"` // the song of yours it is most like
+}
+
+// recommendSongs ranks songs for a user. Collaborative filtering comes first;
+// when that runs short (new users, niche taste) songs sharing a genre,
+// language or artist with their favourites fill in, then popular songs.
+// Liked songs and songs the user already plays are never recommended.
+func recommendSongs(ctx context.Context, viewer songViewer, limit int) ([]recommendedSong, error) {
+	profile, err := buildListenerProfile(ctx, viewer.UserID)
+	if err != nil {
+		return nil, err
+	}
+	seeds := topKeys(profile.affinity, profileSeeds)
+
+	exclude := make(map[string]bool, len(profile.affinity))
+	for songID := range profile.affinity {
+		exclude[songID] = true
+	}
+
+	// 1️⃣ Songs that share listeners with the user's favourites
+	scores := make(map[string]float64)
+	because := make(map[string]string)
+	best := make(map[string]float64)
+	if len(seeds) > 0 {
+		cursor, err := similarityCollection.Find(ctx, bson.M{"song_id": bson.M{"$in": seeds}})
+		if err != nil {
+			return nil, err
+		}
+		var similarities []models.SongSimilarity
+		if err := cursor.All(ctx, &similarities); err != nil {
+			return nil, err
+		}
+		for _, s := range similarities {
+			weight := profile.affinity[s.SongID]
+			for _, neighbour := range s.Similar {
+				if exclude[neighbour.SongID] {
+					continue
+				}
+				contribution := weight * neighbour.Score
+				scores[neighbour.SongID] += contribution
+				if contribution > best[neighbour.SongID] {
+					best[neighbour.SongID] = contribution
+					because[neighbour.SongID] = s.SongID
+				}
+			}
+		}
+	}
+
+	recommendations := []recommendedSong{}
+	if len(scores) > 0 {
+		ranked := topKeys(scores, limit*3)
+		cursor, err := songcollection.Find(ctx,
+			viewer.songFilter(bson.M{"song_id": bson.M{"$in": ranked}}),
+			options.Find().SetProjection(bson.M{"waveform": 0, "user_play_counts": 0}),
+		)
+		if err != nil {
+			return nil, err
+		}
+		var found []models.Song
+		if err := cursor.All(ctx, &found); err != nil {
+			return nil, err
+		}
+		byID := make(map[string]models.Song, len(found))
+		for _, song := range found {
+			byID[song.SongID] = song
+		}
+
+		for _, songID := range ranked {
+			song, ok := byID[songID]
+			if !ok || len(recommendations) == limit {
+				continue
+			}
+			seed := because[songID]
+			recommendations = append(recommendations, recommendedSong{
+				Song: song, Score: scores[songID], Reason: reasonListenedTogether, BecauseOf: &seed,
+			})
+			exclude[songID] = true
+		}
+	}
+
+	// 2️⃣ Same genre, language or artist as the favourites
+	if len(recommendations) < limit && len(seeds) > 0 {
+		taste, err := tasteFilter(ctx, seeds, profile.affinity)
+		if err != nil {
+			return nil, err
+		}
+		if taste != nil {
+			more, err := popularSongs(ctx, viewer, taste, exclude, limit-len(recommendations), reasonYourTaste)
+			if err != nil {
+				return nil, err
+			}
+			recommendations = append(recommendations, more...)
+		}
+	}
+
+	// 3️⃣ Whatever is popular
+	if len(recommendations) < limit {
+		more, err := popularSongs(ctx, viewer, bson.M{}, exclude, limit-len(recommendations), reasonPopular)
+		if err != nil {
+			return nil, err
+		}
+		recommendations = append(recommendations, more...)
+	}
+
+	return recommendations, nil
+}
+
+// tasteFilter matches songs sharing the top genres, languages or artists of
+// the seed songs, weighted by how much the user likes each seed
+func tasteFilter(ctx context.Context, seeds []string, affinity map[string]float64) (bson.M, error) {
+	cursor, err := songcollection.Find(ctx,
+		bson.M{"song_id": bson.M{"$in": seeds}},
+		options.Find().SetProjection(bson.M{"song_id": 1, "genre_slug": 1, "language": 1, "artist_ids": 1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var songs []models.Song
+	if err := cursor.All(ctx, &songs); err != nil {
+		return nil, err
+	}
+
+	genres := make(map[string]float64)
+	languages := make(map[string]float64)
+	artists := make(map[string]float64)
+	for _, song := range songs {
+		weight := affinity[song.SongID]
+		if song.GenreSlug != nil {
+			genres[*song.GenreSlug] += weight
+		}
+		if song.Language != nil && *song.Language != "" {
+			languages[*song.Language] += weight
+		}
+		for _, artistID := range song.ArtistIDs {
+			artists[artistID] += weight
+		}
+	}
+
+	var taste bson.A
+	if len(artists) > 0 {
+		taste = append(taste, bson.M{"artist_ids": bson.M{"$in": topKeys(artists, 5)}})
+	}
+	if len(genres) > 0 {
+		taste = append(taste, bson.M{"genre_slug": bson.M{"$in": topKeys(genres, 3)}})
+	}
+	if len(languages) > 0 {
+		taste = append(taste, bson.M{"language": bson.M{"$in": topKeys(languages, 2)}})
+	}
+	if len(taste) == 0 {
+		return nil, nil
+	}
+	return bson.M{"$or": taste}, nil
+}
+
+// popularSongs fills recommendations with the most played songs matching filter
+func popularSongs(ctx context.Context, viewer songViewer, filter bson.M, exclude map[string]bool, n int, reason string) ([]recommendedSong, error) {
+	excluded := make([]string, 0, len(exclude))
+	for songID := range exclude {
+		excluded = append(excluded, songID)
+	}
+
+	cursor, err := songcollection.Find(ctx,
+		viewer.songFilter(bson.M{"$and": bson.A{filter, bson.M{"song_id": bson.M{"$nin": excluded}}}}),
+		options.Find().
+			SetSort(bson.D{{Key: "play_count", Value: -1}, {Key: "_id", Value: 1}}).
+			SetLimit(int64(n)).
+			SetProjection(bson.M{"waveform": 0, "user_play_counts": 0}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var songs []models.Song
+	if err := cursor.All(ctx, &songs); err != nil {
+		return nil, err
+	}
+
+	recommendations := make([]recommendedSong, 0, len(songs))
+	for _, song := range songs {
+		recommendations = append(recommendations, recommendedSong{Song: song, Reason: reason})
+		exclude[song.SongID] = true
+	}
+	return recommendations, nil
+}
+
+func recommendationLimit(c *gin.Context) int {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 {
+		limit = 20
+	}
+	if limit > 50 {
+		limit = 50
+	}
+	return limit
+}
+
+// RecommendedSongs returns songs picked for the logged-in user
+//
+//	GET /recommendations/songs?limit=20
+func RecommendedSongs() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		songs, err := recommendSongs(ctx, viewerFromContext(c), recommendationLimit(c))
+		if err != nil {
+			log.Println("❌ Failed to build recommendations:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build recommendations"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"songs": songs})
+	}
+}
+
+// RecommendedArtists returns artists the user doesn't follow yet, ranked by
+// how much of their recommended music those artists make
+//
+//	GET /recommendations/artists?limit=20
+func RecommendedArtists() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("user_id")
+		limit := recommendationLimit(c)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		songs, err := recommendSongs(ctx, viewerFromContext(c), 50)
+		if err != nil {
+			log.Println("❌ Failed to build recommendations:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build recommendations"})
+			return
+		}
+
+		// Higher-ranked songs count for more
+		scores := make(map[string]float64)
+		reasons := make(map[string]string)
+		for rank, song := range songs {
+			for _, credit := range song.ArtistCredits {
+				if credit.Role != models.ArtistRolePrimary && credit.Role != models.ArtistRoleFeatured {
+					continue
+				}
+				scores[credit.ArtistID] += 1 / float64(rank+1)
+				if _, ok := reasons[credit.ArtistID]; !ok {
+					reasons[credit.ArtistID] = song.Reason
+				}
+			}
+		}
+
+		type recommendedArtist struct {
+			models.Artist
+			Score  float64 `json:"score"`
+			Reason string  `json:"reason"`
+		}
+		artists := []recommendedArtist{}
+		notFollowed := bson.M{"followers": bson.M{"$ne": userID}}
+		projection := options.Find().SetProjection(bson.M{"followers": 0})
+
+		if len(scores) > 0 {
+			ranked := topKeys(scores, limit*2)
+			cursor, err := artistCollection.Find(ctx,
+				bson.M{"$and": bson.A{notFollowed, bson.M{"artist_id": bson.M{"$in": ranked}}}},
+				projection,
+			)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch artists"})
+				return
+			}
+			var found []models.Artist
+			if err := cursor.All(ctx, &found); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse artists"})
+				return
+			}
+			byID := make(map[string]models.Artist, len(found))
+			for _, artist := range found {
+				byID[artist.Artist_id] = artist
+			}
+			for _, artistID := range ranked {
+				if artist, ok := byID[artistID]; ok && len(artists) < limit {
+					artists = append(artists, recommendedArtist{Artist: artist, Score: scores[artistID], Reason: reasons[artistID]})
+				}
+			}
+		}
+
+		if len(artists) < limit {
+			listed := make([]string, 0, len(artists))
+			for _, artist := range artists {
+				listed = append(listed, artist.Artist_id)
+			}
+			cursor, err := artistCollection.Find(ctx,
+				bson.M{"$and": bson.A{notFollowed, bson.M{"artist_id": bson.M{"$nin": listed}}}},
+				projection.
+					SetSort(bson.D{{Key: "follower_count", Value: -1}, {Key: "_id", Value: 1}}).
+					SetLimit(int64(limit-len(artists))),
+			)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch artists"})
+				return
+			}
+			var popular []models.Artist
+			if err := cursor.All(ctx, &popular); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse artists"})
+				return
+			}
+			for _, artist := range popular {
+				artists = append(artists, recommendedArtist{Artist: artist, Reason: reasonPopular})
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{"artists": artists})
+	}
+}
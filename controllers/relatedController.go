@@ -0,0 +1,357 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ishanbagra18/ecommerce-using-go/database"
+	"github.com/ishanbagra18/ecommerce-using-go/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	sessionGap         = 30 * time.Minute // a longer pause starts a new listening session
+	sessionMaxSongs    = 50
+	sessionLookback    = 60 * 24 * time.Hour
+	sessionMinShared   = 2
+	relatedNeighbours  = 20
+	relatedMaxFollowed = 200 // artists per follower, so a follow-everything account can't dominate
+)
+
+// Songs recommended for their artist or genre when there is no listening data
+const reasonSimilarStyle = "similar_style"
+
+var relatedArtistCollection *mongo.Collection
+
+func InitRelatedController() {
+	relatedArtistCollection = database.OpenCollection(database.Client, "related_artists")
+	log.Println("✔ Related artists collection initialized")
+
+	database.CreateIndexes(relatedArtistCollection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "artist_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+	})
+}
+
+// StartRelatedJob rebuilds related artists every 6 hours. Similar songs come
+// from the song similarity job.
+func StartRelatedJob() {
+	startPeriodicJob("related-artists", 6*time.Hour, 30*time.Minute, computeRelated)
+}
+
+// computeRelated splits play history into listening sessions. Artists are
+// related when their songs share sessions and when the same people follow
+// them, the two weighted equally.
+func computeRelated(ctx context.Context) error {
+	now := time.Now()
+
+	// Primary and featured artists of every song, to turn song sessions into artist sessions
+	songCursor, err := songcollection.Find(ctx, bson.M{"artist_ids.0": bson.M{"$exists": true}},
+		options.Find().SetProjection(bson.M{"song_id": 1, "artist_credits": 1}),
+	)
+	if err != nil {
+		return err
+	}
+	var songs []models.Song
+	if err := songCursor.All(ctx, &songs); err != nil {
+		return err
+	}
+	songArtists := make(map[string][]string, len(songs))
+	for _, song := range songs {
+		for _, credit := range song.ArtistCredits {
+			if credit.Role == models.ArtistRolePrimary || credit.Role == models.ArtistRoleFeatured {
+				songArtists[song.SongID] = append(songArtists[song.SongID], credit.ArtistID)
+			}
+		}
+	}
+
+	// 1️⃣ Sessions: one user's plays with no gap longer than sessionGap
+	cursor, err := historyCollection.Find(ctx,
+		bson.M{"counted": true, "played_at": bson.M{"$gte": now.Add(-sessionLookback)}},
+		options.Find().
+			SetSort(bson.D{{Key: "user_id", Value: 1}, {Key: "played_at", Value: -1}}).
+			SetProjection(bson.M{"user_id": 1, "song_id": 1, "played_at": 1}).
+			SetAllowDiskUse(true),
+	)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	artistSessions := newCoOccurrence()
+	var session []string
+	endSession := func() {
+		if len(session) == 0 {
+			return
+		}
+		var artists []string
+		for _, songID := range session {
+			artists = append(artists, songArtists[songID]...)
+		}
+		artistSessions.add(artists)
+		session = session[:0]
+	}
+
+	var last models.History
+	for cursor.Next(ctx) {
+		var play models.History
+		if err := cursor.Decode(&play); err != nil {
+			return err
+		}
+		// Plays come newest first within each user
+		if play.UserID != last.UserID || last.PlayedAt.Sub(play.PlayedAt) > sessionGap {
+			endSession()
+		}
+		if len(session) < sessionMaxSongs {
+			session = append(session, play.SongID)
+		}
+		last = play
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+	endSession()
+
+	// 2️⃣ Co-follows
+	artistCursor, err := artistCollection.Find(ctx, bson.M{"followers.0": bson.M{"$exists": true}},
+		options.Find().SetProjection(bson.M{"artist_id": 1, "followers": 1}),
+	)
+	if err != nil {
+		return err
+	}
+	var artists []models.Artist
+	if err := artistCursor.All(ctx, &artists); err != nil {
+		return err
+	}
+	followed := make(map[string][]string)
+	for _, artist := range artists {
+		for _, followerID := range artist.Followers {
+			if len(followed[followerID]) < relatedMaxFollowed {
+				followed[followerID] = append(followed[followerID], artist.Artist_id)
+			}
+		}
+	}
+	coFollows := newCoOccurrence()
+	for _, artistIDs := range followed {
+		coFollows.add(artistIDs)
+	}
+
+	// 3️⃣ Store
+	combined := make(map[string]map[string]float64)
+	for _, signal := range []map[string][]scoredID{
+		artistSessions.neighbours(sessionMinShared, relatedNeighbours*2),
+		coFollows.neighbours(sessionMinShared, relatedNeighbours*2),
+	} {
+		for artistID, neighbours := range signal {
+			if combined[artistID] == nil {
+				combined[artistID] = make(map[string]float64)
+			}
+			for _, neighbour := range neighbours {
+				combined[artistID][neighbour.ID] += neighbour.Score / 2
+			}
+		}
+	}
+	writer := newComputedWriter(ctx, relatedArtistCollection, now)
+	for artistID, scores := range combined {
+		related := make([]models.RelatedArtist, 0, relatedNeighbours)
+		for _, relatedID := range topKeys(scores, relatedNeighbours) {
+			related = append(related, models.RelatedArtist{ArtistID: relatedID, Score: scores[relatedID]})
+		}
+		doc := models.RelatedArtists{ArtistID: artistID, Related: related, ComputedAt: now}
+		if err := writer.replace(bson.M{"artist_id": artistID}, doc); err != nil {
+			return err
+		}
+	}
+	if err := writer.finish(); err != nil {
+		return err
+	}
+
+	log.Printf("🧠 Related artists rebuilt: %d artists with related artists\n", len(combined))
+	return nil
+}
+
+// GetSimilarSongs returns songs the same listeners play as this one.
+// Songs without enough listening data get songs by the same artists or in
+// the same genre instead.
+//
+//	GET /song/:song_id/similar?limit=20
+func GetSimilarSongs() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		songID := c.Param("song_id")
+		limit := recommendationLimit(c)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var song models.Song
+		err := songcollection.FindOne(ctx, bson.M{"song_id": songID}).Decode(&song)
+		if err != nil || !viewerFromContext(c).canView(song) {
+			if err != nil && err != mongo.ErrNoDocuments {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch song"})
+				return
+			}
+			c.JSON(http.StatusNotFound, gin.H{"error": "song not found"})
+			return
+		}
+
+		var similarity models.SongSimilarity
+		err = similarityCollection.FindOne(ctx, bson.M{"song_id": songID}).Decode(&similarity)
+		if err != nil && err != mongo.ErrNoDocuments {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch similar songs"})
+			return
+		}
+
+		songs := []recommendedSong{}
+		exclude := map[string]bool{songID: true}
+		if len(similarity.Similar) > 0 {
+			ids := make([]string, 0, len(similarity.Similar))
+			scores := make(map[string]float64, len(similarity.Similar))
+			for _, s := range similarity.Similar {
+				ids = append(ids, s.SongID)
+				scores[s.SongID] = s.Score
+			}
+
+			cursor, err := songcollection.Find(ctx,
+				publicSongFilter(c, bson.M{"song_id": bson.M{"$in": ids}}),
+				options.Find().SetProjection(bson.M{"waveform": 0, "user_play_counts": 0}),
+			)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch similar songs"})
+				return
+			}
+			var found []models.Song
+			if err := cursor.All(ctx, &found); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to parse songs"})
+				return
+			}
+			byID := make(map[string]models.Song, len(found))
+			for _, s := range found {
+				byID[s.SongID] = s
+			}
+			for _, id := range ids {
+				if s, ok := byID[id]; ok && len(songs) < limit {
+					songs = append(songs, recommendedSong{Song: s, Score: scores[id], Reason: reasonListenedTogether})
+					exclude[id] = true
+				}
+			}
+		}
+
+		if len(songs) < limit {
+			var style bson.A
+			if len(song.ArtistIDs) > 0 {
+				style = append(style, bson.M{"artist_ids": bson.M{"$in": song.ArtistIDs}})
+			}
+			if song.GenreSlug != nil {
+				style = append(style, bson.M{"genre_slug": *song.GenreSlug})
+			}
+			if len(style) > 0 {
+				more, err := popularSongs(ctx, viewerFromContext(c), bson.M{"$or": style}, exclude, limit-len(songs), reasonSimilarStyle)
+				if err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch similar songs"})
+					return
+				}
+				songs = append(songs, more...)
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{"song_id": songID, "songs": songs})
+	}
+}
+
+// GetRelatedArtists returns artists whose listeners and followers overlap with
+// this one's, falling back to popular artists in the same genres
+//
+//	GET /artists/:artist_id/related?limit=20
+func GetRelatedArtists() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		artistID := c.Param("artist_id")
+		limit := recommendationLimit(c)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var artist models.Artist
+		if err := artistCollection.FindOne(ctx, bson.M{"artist_id": artistID}).Decode(&artist); err != nil {
+			if err == mongo.ErrNoDocuments {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Artist not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch artist"})
+			return
+		}
+
+		var related models.RelatedArtists
+		err := relatedArtistCollection.FindOne(ctx, bson.M{"artist_id": artistID}).Decode(&related)
+		if err != nil && err != mongo.ErrNoDocuments {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch related artists"})
+			return
+		}
+
+		type relatedArtist struct {
+			models.Artist
+			Score  float64 `json:"score"`
+			Reason string  `json:"reason"`
+		}
+		artists := []relatedArtist{}
+		listed := []string{artistID}
+		projection := bson.M{"followers": 0}
+
+		if len(related.Related) > 0 {
+			ids := make([]string, 0, len(related.Related))
+			for _, r := range related.Related {
+				ids = append(ids, r.ArtistID)
+			}
+			cursor, err := artistCollection.Find(ctx, bson.M{"artist_id": bson.M{"$in": ids}},
+				options.Find().SetProjection(projection),
+			)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch related artists"})
+				return
+			}
+			var found []models.Artist
+			if err := cursor.All(ctx, &found); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse artists"})
+				return
+			}
+			byID := make(map[string]models.Artist, len(found))
+			for _, a := range found {
+				byID[a.Artist_id] = a
+			}
+			for _, r := range related.Related {
+				if a, ok := byID[r.ArtistID]; ok && len(artists) < limit {
+					artists = append(artists, relatedArtist{Artist: a, Score: r.Score, Reason: reasonListenedTogether})
+					listed = append(listed, r.ArtistID)
+				}
+			}
+		}
+
+		if len(artists) < limit && len(artist.Genre) > 0 {
+			cursor, err := artistCollection.Find(ctx,
+				bson.M{"genre": bson.M{"$in": artist.Genre}, "artist_id": bson.M{"$nin": listed}},
+				options.Find().
+					SetProjection(projection).
+					SetSort(bson.D{{Key: "follower_count", Value: -1}, {Key: "_id", Value: 1}}).
+					SetLimit(int64(limit-len(artists))),
+			)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch related artists"})
+				return
+			}
+			var sameGenre []models.Artist
+			if err := cursor.All(ctx, &sameGenre); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse artists"})
+				return
+			}
+			for _, a := range sameGenre {
+				artists = append(artists, relatedArtist{Artist: a, Reason: reasonSimilarStyle})
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{"artist_id": artistID, "artists": artists})
+	}
+}
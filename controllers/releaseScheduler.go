@@ -0,0 +1,164 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ishanbagra18/ecommerce-using-go/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// How long an instance has to notify a release's followers before another
+// one takes over, e.g. after a crash or a failed attempt
+const releaseNotifyLease = 10 * time.Minute
+
+// StartReleaseScheduler releases embargoed songs once their release_date
+// has passed and tells the artists' followers about them
+func StartReleaseScheduler() {
+	startPeriodicJob("release-scheduler", time.Minute, 50*time.Second, func(ctx context.Context) error {
+		if err := releaseDueSongs(ctx); err != nil {
+			return err
+		}
+		return notifyReleasedSongs(ctx)
+	})
+}
+
+// releaseDueSongs flips is_released on songs whose release time has come
+func releaseDueSongs(ctx context.Context) error {
+	result, err := songcollection.UpdateMany(ctx,
+		bson.M{
+			"is_released":  false,
+			"release_date": bson.M{"$lte": time.Now()},
+		},
+		bson.M{"$set": bson.M{"is_released": true, "updated_at": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.ModifiedCount > 0 {
+		log.Printf("🚀 Released %d scheduled songs\n", result.ModifiedCount)
+	}
+	return nil
+}
+
+// notifyReleasedSongs notifies followers about scheduled songs that are now live.
+// Songs still waiting for moderation are picked up once they are approved.
+// A song is only marked notified once that worked; until then it is retried
+// when its lease runs out.
+func notifyReleasedSongs(ctx context.Context) error {
+	now := time.Now()
+	filter := bson.M{
+		"is_released":      true,
+		"release_notified": false,
+		"status":           models.SongStatusApproved,
+		"$or": bson.A{
+			bson.M{"notifying_at": nil},
+			bson.M{"notifying_at": bson.M{"$lt": now.Add(-releaseNotifyLease)}},
+		},
+	}
+
+	for {
+		// Claim one song at a time so two instances never notify at once
+		var song models.Song
+		err := songcollection.FindOneAndUpdate(ctx, filter,
+			bson.M{"$set": bson.M{"notifying_at": now}},
+		).Decode(&song)
+		if err == mongo.ErrNoDocuments {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := notifyFollowersOfRelease(ctx, song); err != nil {
+			log.Printf("⚠️ Failed to notify followers about song %s, will retry: %v\n", song.SongID, err)
+			continue
+		}
+		if _, err := songcollection.UpdateOne(ctx,
+			bson.M{"song_id": song.SongID},
+			bson.M{"$set": bson.M{"release_notified": true}, "$unset": bson.M{"notifying_at": ""}},
+		); err != nil {
+			return err
+		}
+	}
+}
+
+// notifyFollowersOfRelease sends a new_release notification to everyone
+// following one of the song's artists. Followers an earlier, interrupted
+// attempt already told are skipped.
+func notifyFollowersOfRelease(ctx context.Context, song models.Song) error {
+	// Followers hear about songs their artist performs on, not ones they produced
+	var artistIDs []string
+	for _, credit := range song.ArtistCredits {
+		if credit.Role == models.ArtistRolePrimary || credit.Role == models.ArtistRoleFeatured {
+			artistIDs = append(artistIDs, credit.ArtistID)
+		}
+	}
+	if len(artistIDs) == 0 {
+		return nil
+	}
+
+	cursor, err := artistCollection.Find(ctx,
+		bson.M{"artist_id": bson.M{"$in": artistIDs}},
+		options.Find().SetProjection(bson.M{"artist_id": 1, "name": 1, "followers": 1}),
+	)
+	if err != nil {
+		return err
+	}
+
+	var artists []models.Artist
+	if err := cursor.All(ctx, &artists); err != nil {
+		return err
+	}
+
+	title := ""
+	if song.Title != nil {
+		title = *song.Title
+	}
+
+	notified := make(map[string]bool)
+	already, err := notificationCollection.Distinct(ctx, "user_id", bson.M{
+		"type":    models.NotificationNewRelease,
+		"song_id": song.SongID,
+	})
+	if err != nil {
+		return err
+	}
+	for _, userID := range already {
+		if id, ok := userID.(string); ok {
+			notified[id] = true
+		}
+	}
+	told := len(notified)
+
+	for _, artist := range artists {
+		var followers []string
+		for _, followerID := range artist.Followers {
+			if !notified[followerID] {
+				notified[followerID] = true
+				followers = append(followers, followerID)
+			}
+		}
+
+		songID := song.SongID
+		artistID := artist.Artist_id
+		err := createNotifications(ctx, followers, models.Notification{
+			Type:     models.NotificationNewRelease,
+			Message:  fmt.Sprintf("%s just released \"%s\"", *artist.Name, title),
+			SongID:   &songID,
+			ArtistID: &artistID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Printf("🔔 Notified %d followers about release of song %s\n", len(notified)-told, song.SongID)
+	return nil
+}
@@ -0,0 +1,343 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ishanbagra18/ecommerce-using-go/helpers"
+	"github.com/ishanbagra18/ecommerce-using-go/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Search results embed the document plus the boosted relevance score
+type songSearchHit struct {
+	models.Song `bson:",inline"`
+	Score       float64 `bson:"score" json:"score"`
+}
+
+type artistSearchHit struct {
+	models.Artist `bson:",inline"`
+	Score         float64 `bson:"score" json:"score"`
+}
+
+type playlistSearchHit struct {
+	models.Playlist `bson:",inline"`
+	Score           float64 `bson:"score" json:"score"`
+}
+
+type albumSearchHit struct {
+	models.Album `bson:",inline"`
+	Score        float64 `bson:"score" json:"score"`
+}
+
+type searchGroup struct {
+	Items interface{} `json:"items"`
+	Total int64       `json:"total"`
+}
+
+var searchGroups = []string{"songs", "artists", "playlists", "albums"}
+
+// UnifiedSearch searches songs, artists, playlists and albums in one call.
+// Results are ranked by text relevance boosted by popularity and paginated per group.
+//
+//	GET /search?q=tere+bina&type=songs&page=1&limit=10
+func UnifiedSearch() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := strings.TrimSpace(c.Query("q"))
+		if query == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "query parameter 'q' is required"})
+			return
+		}
+
+		groupType := strings.ToLower(strings.TrimSpace(c.Query("type")))
+		groups := searchGroups
+		if groupType != "" {
+			if !containsString(searchGroups, groupType) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid type, use one of: songs | artists | playlists | albums"})
+				return
+			}
+			groups = []string{groupType}
+		}
+
+		page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+		if err != nil || page < 1 {
+			page = 1
+		}
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+		if err != nil || limit < 1 {
+			limit = 10
+		}
+		if limit > 50 {
+			limit = 50
+		}
+		skip := (page - 1) * limit
+
+		log.Printf("🔍 Unified search for %q in %v (page %d)\n", query, groups, page)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		results := gin.H{}
+		for _, group := range groups {
+			var result searchGroup
+			var err error
+
+			switch group {
+			case "songs":
+				result, err = searchSongs(ctx, c, query, skip, limit)
+			case "artists":
+				result, err = searchArtists(ctx, query, skip, limit)
+			case "playlists":
+				result, err = searchPlaylists(ctx, query, skip, limit)
+			case "albums":
+				result, err = searchAlbums(ctx, viewerFromContext(c), query, skip, limit)
+			}
+
+			if err != nil {
+				log.Printf("❌ Search in %s failed: %v\n", group, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Search failed"})
+				return
+			}
+			results[group] = result
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"query":   query,
+			"page":    page,
+			"limit":   limit,
+			"results": results,
+		})
+	}
+}
+
+func searchSongs(ctx context.Context, c *gin.Context, query string, skip int, limit int) (searchGroup, error) {
+	match := publicSongFilter(c, bson.M{"$text": bson.M{"$search": query}})
+	boost := popularityBoost(bson.M{"$add": bson.A{
+		bson.M{"$ifNull": bson.A{"$play_count", 0}},
+		bson.M{"$multiply": bson.A{2, bson.M{"$ifNull": bson.A{"$like_count", 0}}}},
+	}})
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$addFields", Value: bson.M{"score": bson.M{"$multiply": bson.A{bson.M{"$meta": "textScore"}, boost}}}}},
+	}
+
+	var items []songSearchHit
+	total, err := runSearchPipeline(ctx, songcollection, pipeline, skip, limit, &items)
+	if items == nil {
+		items = []songSearchHit{}
+	}
+	return searchGroup{Items: items, Total: total}, err
+}
+
+func searchArtists(ctx context.Context, query string, skip int, limit int) (searchGroup, error) {
+	boost := popularityBoost(bson.M{"$ifNull": bson.A{"$follower_count", 0}})
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"$text": bson.M{"$search": query}}}},
+		{{Key: "$addFields", Value: bson.M{"score": bson.M{"$multiply": bson.A{bson.M{"$meta": "textScore"}, boost}}}}},
+	}
+
+	var items []artistSearchHit
+	total, err := runSearchPipeline(ctx, artistCollection, pipeline, skip, limit, &items)
+	if items == nil {
+		items = []artistSearchHit{}
+	}
+	return searchGroup{Items: items, Total: total}, err
+}
+
+func searchPlaylists(ctx context.Context, query string, skip int, limit int) (searchGroup, error) {
+	boost := popularityBoost(bson.M{"$ifNull": bson.A{"$play_count", 0}})
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"$text": bson.M{"$search": query}, "is_public": true}}},
+		{{Key: "$addFields", Value: bson.M{"score": bson.M{"$multiply": bson.A{bson.M{"$meta": "textScore"}, boost}}}}},
+	}
+
+	var items []playlistSearchHit
+	total, err := runSearchPipeline(ctx, playlistCollection, pipeline, skip, limit, &items)
+	if items == nil {
+		items = []playlistSearchHit{}
+	}
+	return searchGroup{Items: items, Total: total}, err
+}
+
+// searchAlbums ranks albums by text relevance, boosted by their number of tracks
+func searchAlbums(ctx context.Context, viewer songViewer, query string, skip int, limit int) (searchGroup, error) {
+	boost := popularityBoost(bson.M{"$size": "$tracks"})
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"$text": bson.M{"$search": query}, "tracks.0": bson.M{"$exists": true}}}},
+		{{Key: "$addFields", Value: bson.M{"score": bson.M{"$multiply": bson.A{bson.M{"$meta": "textScore"}, boost}}}}},
+	}
+	pipeline = append(pipeline, visibleAlbumStages(viewer)...)
+
+	var items []albumSearchHit
+	total, err := runSearchPipeline(ctx, albumCollection, pipeline, skip, limit, &items)
+	if items == nil {
+		items = []albumSearchHit{}
+	}
+	return searchGroup{Items: items, Total: total}, err
+}
+
+// How many index hits are checked against visibility before paginating
+const fuzzySearchCandidates = 200
+
+// FuzzySearchSongs finds songs by title, artist or album while tolerating typos,
+// romanisation variants and Devanagari/Gurmukhi input ("tery bina", "तेरे बिना").
+//
+//	GET /search/songs?q=tery+bina&page=1&limit=20
+func FuzzySearchSongs() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := strings.TrimSpace(c.Query("q"))
+		if query == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "query parameter 'q' is required"})
+			return
+		}
+
+		page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+		if err != nil || page < 1 {
+			page = 1
+		}
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+		if err != nil || limit < 1 {
+			limit = 20
+		}
+		if limit > 50 {
+			limit = 50
+		}
+
+		hits, err := helpers.SearchSongIndex(query, fuzzySearchCandidates)
+		if err == helpers.ErrSongIndexNotReady {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Search is starting up, try again shortly"})
+			return
+		}
+		if err != nil {
+			log.Printf("❌ Fuzzy search for %q failed: %v\n", query, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Search failed"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		ids := make([]string, 0, len(hits))
+		for _, hit := range hits {
+			ids = append(ids, hit.SongID)
+		}
+
+		// The index knows nothing about moderation or embargoes, so hits are
+		// filtered through the same visibility rules as every other listing
+		cursor, err := songcollection.Find(ctx,
+			publicSongFilter(c, bson.M{"song_id": bson.M{"$in": ids}}),
+			options.Find().SetProjection(bson.M{"waveform": 0, "user_play_counts": 0}),
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching songs"})
+			return
+		}
+		defer cursor.Close(ctx)
+
+		var found []models.Song
+		if err := cursor.All(ctx, &found); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error parsing songs"})
+			return
+		}
+
+		byID := make(map[string]models.Song, len(found))
+		for _, song := range found {
+			byID[song.SongID] = song
+		}
+
+		// Keep the index's relevance order
+		visible := make([]songSearchHit, 0, len(found))
+		for _, hit := range hits {
+			if song, ok := byID[hit.SongID]; ok {
+				visible = append(visible, songSearchHit{Song: song, Score: hit.Score})
+			}
+		}
+
+		start := (page - 1) * limit
+		if start > len(visible) {
+			start = len(visible)
+		}
+		end := start + limit
+		if end > len(visible) {
+			end = len(visible)
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"query":      query,
+			"normalized": helpers.NormalizeSearchText(query),
+			"page":       page,
+			"limit":      limit,
+			"total":      len(visible),
+			"songs":      visible[start:end],
+		})
+	}
+}
+
+// popularityBoost is 1 + log10(1 + popularity), so popular results rank higher
+// without drowning out relevance
+func popularityBoost(popularity interface{}) bson.M {
+	return bson.M{"$add": bson.A{1, bson.M{"$log10": bson.M{"$add": bson.A{1, popularity}}}}}
+}
+
+// runSearchPipeline sorts by score and returns one page plus the total match count
+func runSearchPipeline(ctx context.Context, collection *mongo.Collection, pipeline mongo.Pipeline, skip int, limit int, out interface{}) (int64, error) {
+	pipeline = append(pipeline, bson.D{{Key: "$facet", Value: bson.M{
+		"items": bson.A{
+			bson.M{"$sort": bson.D{{Key: "score", Value: -1}, {Key: "_id", Value: 1}}},
+			bson.M{"$skip": skip},
+			bson.M{"$limit": limit},
+			bson.M{"$project": bson.M{"waveform": 0, "user_play_counts": 0}},
+		},
+		"total": bson.A{bson.M{"$count": "count"}},
+	}}})
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var result []struct {
+		Items bson.RawValue `bson:"items"`
+		Total []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+	}
+	if err := cursor.All(ctx, &result); err != nil {
+		return 0, err
+	}
+	if len(result) == 0 {
+		return 0, nil
+	}
+
+	if err := result[0].Items.Unmarshal(out); err != nil {
+		return 0, err
+	}
+
+	var total int64
+	if len(result[0].Total) > 0 {
+		total = result[0].Total[0].Count
+	}
+	return total, nil
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
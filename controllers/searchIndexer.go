@@ -0,0 +1,137 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ishanbagra18/ecommerce-using-go/helpers"
+	"github.com/ishanbagra18/ecommerce-using-go/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// StartSearchIndexer builds the in-memory song search index from the songs
+// collection and rebuilds it periodically. Writes made through this instance
+// are indexed straight away; the rebuild catches changes made elsewhere.
+func StartSearchIndexer() {
+	if err := helpers.InitSongIndex(); err != nil {
+		log.Printf("❌ Failed to create song search index: %v\n", err)
+		return
+	}
+
+	startPeriodicJob("search-indexer", 15*time.Minute, 5*time.Minute, rebuildSongIndex)
+}
+
+// rebuildSongIndex re-indexes every song and drops songs that no longer exist
+func rebuildSongIndex(ctx context.Context) error {
+	cursor, err := songcollection.Find(ctx, bson.M{},
+		options.Find().SetProjection(bson.M{"song_id": 1, "title": 1, "artist": 1, "artists": 1, "album": 1}),
+	)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	seen := make(map[string]bool)
+	batch := make([]helpers.SearchableSong, 0, 500)
+	for cursor.Next(ctx) {
+		var song models.Song
+		if err := cursor.Decode(&song); err != nil {
+			return err
+		}
+		seen[song.SongID] = true
+		batch = append(batch, searchableSong(song))
+
+		if len(batch) == cap(batch) {
+			if err := helpers.IndexSongs(batch); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+	if err := helpers.IndexSongs(batch); err != nil {
+		return err
+	}
+
+	indexed, err := helpers.IndexedSongIDs()
+	if err != nil {
+		return err
+	}
+	var stale []string
+	for _, songID := range indexed {
+		if !seen[songID] {
+			stale = append(stale, songID)
+		}
+	}
+	if len(stale) > 0 {
+		if err := helpers.RemoveSongsFromIndex(stale...); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("🔍 Search index rebuilt: %d songs, %d removed\n", len(seen), len(stale))
+	return nil
+}
+
+// indexSong adds or refreshes one song in the search index after a write
+func indexSong(song models.Song) {
+	if err := helpers.IndexSongs([]helpers.SearchableSong{searchableSong(song)}); err != nil {
+		log.Printf("⚠️ Failed to index song %s: %v\n", song.SongID, err)
+	}
+}
+
+// reindexSongs refreshes the index entries of every song matching filter,
+// after a change that touched several songs at once
+func reindexSongs(ctx context.Context, filter bson.M) {
+	cursor, err := songcollection.Find(ctx, filter,
+		options.Find().SetProjection(bson.M{"song_id": 1, "title": 1, "artist": 1, "artists": 1, "album": 1}),
+	)
+	if err != nil {
+		log.Printf("⚠️ Failed to load songs for reindexing: %v\n", err)
+		return
+	}
+
+	var songs []models.Song
+	if err := cursor.All(ctx, &songs); err != nil {
+		log.Printf("⚠️ Failed to load songs for reindexing: %v\n", err)
+		return
+	}
+
+	batch := make([]helpers.SearchableSong, 0, len(songs))
+	for _, song := range songs {
+		batch = append(batch, searchableSong(song))
+	}
+	if err := helpers.IndexSongs(batch); err != nil {
+		log.Printf("⚠️ Failed to reindex %d songs: %v\n", len(batch), err)
+	}
+}
+
+// unindexSong removes a deleted song from the search index
+func unindexSong(songID string) {
+	if err := helpers.RemoveSongsFromIndex(songID); err != nil {
+		log.Printf("⚠️ Failed to remove song %s from search index: %v\n", songID, err)
+	}
+}
+
+func searchableSong(song models.Song) helpers.SearchableSong {
+	s := helpers.SearchableSong{SongID: song.SongID}
+	if song.Title != nil {
+		s.Title = *song.Title
+	}
+	if song.Album != nil {
+		s.Album = *song.Album
+	}
+
+	artists := song.Artists
+	if song.Artist != nil {
+		artists = append([]string{*song.Artist}, artists...)
+	}
+	s.Artist = strings.Join(artists, " ")
+	return s
+}
@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ishanbagra18/ecommerce-using-go/helpers"
+	"github.com/ishanbagra18/ecommerce-using-go/models"
+)
+
+// availabilityFromForm applies the licensing fields of an upload or edit form
+// to the song's current rules. Only the fields that are sent change; an empty
+// field clears that rule. It reports whether any field was sent.
+//
+//	allowed_countries=US,CA  blocked_countries=DE  available_from=2025-01-01  available_until=2026-01-01
+func availabilityFromForm(c *gin.Context, current *models.SongAvailability) (*models.SongAvailability, bool, error) {
+	rules := models.SongAvailability{}
+	if current != nil {
+		rules = *current
+	}
+	sent := false
+
+	countries := func(key string, list *[]string) error {
+		if _, ok := c.GetPostForm(key); !ok {
+			return nil
+		}
+		sent = true
+		var codes []string
+		for _, value := range formList(c, key) {
+			code, ok := helpers.NormalizeCountryCode(value)
+			if !ok {
+				return errors.New(key + " must be two-letter country codes")
+			}
+			if !containsString(codes, code) {
+				codes = append(codes, code)
+			}
+		}
+		*list = codes
+		return nil
+	}
+	if err := countries("allowed_countries", &rules.AllowedCountries); err != nil {
+		return nil, true, err
+	}
+	if err := countries("blocked_countries", &rules.BlockedCountries); err != nil {
+		return nil, true, err
+	}
+
+	date := func(key string, at **time.Time) error {
+		value, ok := c.GetPostForm(key)
+		if !ok {
+			return nil
+		}
+		sent = true
+		value = strings.TrimSpace(value)
+		if value == "" {
+			*at = nil
+			return nil
+		}
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			*at = &t
+			return nil
+		}
+		if t, err := time.Parse("2006-01-02", value); err == nil {
+			*at = &t
+			return nil
+		}
+		return errors.New(key + " must be a date (YYYY-MM-DD or RFC 3339)")
+	}
+	if err := date("available_from", &rules.StartsAt); err != nil {
+		return nil, true, err
+	}
+	if err := date("available_until", &rules.EndsAt); err != nil {
+		return nil, true, err
+	}
+
+	if !sent {
+		return current, false, nil
+	}
+	for _, code := range rules.BlockedCountries {
+		if containsString(rules.AllowedCountries, code) {
+			return nil, true, errors.New(code + " can't be both allowed and blocked")
+		}
+	}
+	if rules.StartsAt != nil && rules.EndsAt != nil && !rules.EndsAt.After(*rules.StartsAt) {
+		return nil, true, errors.New("available_until must be after available_from")
+	}
+
+	if len(rules.AllowedCountries) == 0 && len(rules.BlockedCountries) == 0 && rules.StartsAt == nil && rules.EndsAt == nil {
+		return nil, true, nil
+	}
+	return &rules, true, nil
+}
@@ -43,6 +43,7 @@ func GetMyListeningStats() gin.HandlerFunc {
 		// Get all history entries for this user in the time range
 		filter := bson.M{
 			"user_id": userID.(string),
+			"counted": true,
 			"played_at": bson.M{
 				"$gte": startTime,
 				"$lte": now,
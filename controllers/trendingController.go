@@ -0,0 +1,293 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ishanbagra18/ecommerce-using-go/database"
+	"github.com/ishanbagra18/ecommerce-using-go/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	trendingHalfLife = 24 * time.Hour     // a play counts half as much a day later
+	trendingLookback = 8 * 24 * time.Hour // the last 24h plus the week before it
+	trendingListSize = 50
+)
+
+var trendingCollection *mongo.Collection
+
+// trendingCache holds the latest lists by scope so requests don't need the database
+var trendingCache = struct {
+	sync.RWMutex
+	lists map[string][]models.TrendingEntry
+}{lists: map[string][]models.TrendingEntry{}}
+
+func InitTrendingController() {
+	trendingCollection = database.OpenCollection(database.Client, "trending")
+	log.Println("✔ Trending collection initialized")
+
+	database.CreateIndexes(trendingCollection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "scope", Value: 1}}, Options: options.Index().SetUnique(true)},
+	})
+}
+
+// StartTrendingJob recomputes the trending lists from recent plays every 10 minutes
+func StartTrendingJob() {
+	startPeriodicJob("trending", 10*time.Minute, 5*time.Minute, computeTrending)
+}
+
+func trendingScope(kind string, value string) string {
+	if value = strings.ToLower(strings.TrimSpace(value)); value == "" {
+		return ""
+	}
+	return kind + ":" + value
+}
+
+// computeTrending scores every song played in the lookback window.
+// Each play is worth exp(-age/halfLife·ln2), so the sum fades as plays get older,
+// and that sum is scaled by velocity: plays in the last 24h against the daily
+// average of the week before. A new song taking off beats an old hit holding steady.
+func computeTrending(ctx context.Context) error {
+	now := time.Now()
+	dayAgo := now.Add(-24 * time.Hour)
+	decayPerMs := -math.Ln2 / float64(trendingHalfLife.Milliseconds())
+
+	cursor, err := historyCollection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"played_at": bson.M{"$gte": now.Add(-trendingLookback)}, "counted": true}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": "$song_id",
+			"decayed": bson.M{"$sum": bson.M{"$exp": bson.M{"$multiply": bson.A{
+				decayPerMs, bson.M{"$subtract": bson.A{now, "$played_at"}},
+			}}}},
+			"plays_24h":   bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$gte": bson.A{"$played_at", dayAgo}}, 1, 0}}},
+			"plays_prior": bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$lt": bson.A{"$played_at", dayAgo}}, 1, 0}}},
+		}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	var stats []struct {
+		SongID     string  `bson:"_id"`
+		Decayed    float64 `bson:"decayed"`
+		Plays24h   int     `bson:"plays_24h"`
+		PlaysPrior int     `bson:"plays_prior"`
+	}
+	if err := cursor.All(ctx, &stats); err != nil {
+		return err
+	}
+
+	ids := make([]string, 0, len(stats))
+	for _, s := range stats {
+		ids = append(ids, s.SongID)
+	}
+
+	// Only songs anyone can see may trend; licensing is checked when trending is served
+	songCursor, err := songcollection.Find(ctx,
+		songViewer{AnyRegion: true}.songFilter(bson.M{"song_id": bson.M{"$in": ids}}),
+		options.Find().SetProjection(bson.M{"song_id": 1, "genre": 1, "language": 1}),
+	)
+	if err != nil {
+		return err
+	}
+	var songs []models.Song
+	if err := songCursor.All(ctx, &songs); err != nil {
+		return err
+	}
+	songsByID := make(map[string]models.Song, len(songs))
+	for _, song := range songs {
+		songsByID[song.SongID] = song
+	}
+
+	lists := map[string][]models.TrendingEntry{"global": {}}
+	for _, s := range stats {
+		song, ok := songsByID[s.SongID]
+		if !ok {
+			continue
+		}
+
+		velocity := float64(s.Plays24h+1) / (float64(s.PlaysPrior)/7 + 1)
+		entry := models.TrendingEntry{
+			SongID:   s.SongID,
+			Score:    s.Decayed * math.Sqrt(velocity),
+			Plays24h: s.Plays24h,
+			Velocity: velocity,
+		}
+
+		lists["global"] = append(lists["global"], entry)
+		if song.Genre != nil {
+			if scope := trendingScope("genre", *song.Genre); scope != "" {
+				lists[scope] = append(lists[scope], entry)
+			}
+		}
+		if song.Language != nil {
+			if scope := trendingScope("language", *song.Language); scope != "" {
+				lists[scope] = append(lists[scope], entry)
+			}
+		}
+	}
+
+	scopes := make([]string, 0, len(lists))
+	for scope, entries := range lists {
+		sort.Slice(entries, func(i, j int) bool {
+			if entries[i].Score != entries[j].Score {
+				return entries[i].Score > entries[j].Score
+			}
+			return entries[i].SongID < entries[j].SongID
+		})
+		if len(entries) > trendingListSize {
+			entries = entries[:trendingListSize]
+		}
+		lists[scope] = entries
+		scopes = append(scopes, scope)
+
+		_, err := trendingCollection.ReplaceOne(ctx,
+			bson.M{"scope": scope},
+			models.TrendingList{Scope: scope, Songs: entries, ComputedAt: now},
+			options.Replace().SetUpsert(true),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Genres and languages with no recent plays drop out
+	if _, err := trendingCollection.DeleteMany(ctx, bson.M{"scope": bson.M{"$nin": scopes}}); err != nil {
+		return err
+	}
+
+	trendingCache.Lock()
+	trendingCache.lists = lists
+	trendingCache.Unlock()
+
+	log.Printf("🔥 Trending recomputed: %d songs across %d lists\n", len(lists["global"]), len(lists))
+	return nil
+}
+
+// trendingEntries reads a list from the cache, falling back to the database
+// when another instance computed it
+func trendingEntries(ctx context.Context, scope string) ([]models.TrendingEntry, error) {
+	trendingCache.RLock()
+	entries, ok := trendingCache.lists[scope]
+	trendingCache.RUnlock()
+	if ok {
+		return entries, nil
+	}
+
+	var list models.TrendingList
+	err := trendingCollection.FindOne(ctx, bson.M{"scope": scope}).Decode(&list)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return list.Songs, nil
+}
+
+// TrendingSongs returns what is hot right now, globally or for one genre or language.
+// With no recent plays yet it falls back to lifetime play count.
+//
+//	GET /music/trendingsongs?genre=pop&limit=10
+//	GET /music/trendingsongs?language=punjabi
+func TrendingSongs() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scope := "global"
+		fallback := bson.M{}
+		if genre := c.Query("genre"); genre != "" {
+			scope = trendingScope("genre", genre)
+			fallback = bson.M{"genre": bson.M{"$in": exactPatterns([]string{genre})}}
+		} else if language := c.Query("language"); language != "" {
+			scope = trendingScope("language", language)
+			fallback = bson.M{"language": bson.M{"$in": exactPatterns([]string{language})}}
+		}
+
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+		if err != nil || limit < 1 {
+			limit = 10
+		}
+		if limit > trendingListSize {
+			limit = trendingListSize
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		entries, err := trendingEntries(ctx, scope)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch trending songs"})
+			return
+		}
+
+		if len(entries) == 0 {
+			opts := options.Find().
+				SetSort(bson.D{{Key: "play_count", Value: -1}}).
+				SetLimit(int64(limit))
+
+			cursor, err := songcollection.Find(ctx, publicSongFilter(c, fallback), opts)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch trending songs"})
+				return
+			}
+
+			var songs []models.Song
+			if err := cursor.All(ctx, &songs); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to parse songs"})
+				return
+			}
+			if songs == nil {
+				songs = []models.Song{}
+			}
+
+			c.JSON(http.StatusOK, gin.H{"songs": songs, "scope": scope})
+			return
+		}
+
+		ids := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			ids = append(ids, entry.SongID)
+		}
+
+		cursor, err := songcollection.Find(ctx, publicSongFilter(c, bson.M{"song_id": bson.M{"$in": ids}}))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch trending songs"})
+			return
+		}
+
+		var found []models.Song
+		if err := cursor.All(ctx, &found); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to parse songs"})
+			return
+		}
+		byID := make(map[string]models.Song, len(found))
+		for _, song := range found {
+			byID[song.SongID] = song
+		}
+
+		type trendingSong struct {
+			models.Song
+			TrendingScore float64 `json:"trending_score"`
+			Velocity      float64 `json:"velocity"`
+		}
+		songs := make([]trendingSong, 0, limit)
+		for _, entry := range entries {
+			if song, ok := byID[entry.SongID]; ok && len(songs) < limit {
+				songs = append(songs, trendingSong{Song: song, TrendingScore: entry.Score, Velocity: entry.Velocity})
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{"songs": songs, "scope": scope})
+	}
+}
@@ -232,6 +232,10 @@ func UpdateProfile() gin.HandlerFunc {
 			updateObj["phone"] = user.Phone
 		}
 
+		if user.HideExplicit != nil {
+			updateObj["hide_explicit"] = *user.HideExplicit
+		}
+
 		updateObj["updated_at"] = time.Now()
 
 		filter := bson.M{"user_id": userId}
@@ -77,6 +77,20 @@ func OpenCollection(client *mongo.Client, collectionName string) *mongo.Collecti
 	return client.Database("ecommerce").Collection(collectionName)
 }
 
+// CreateIndexes makes sure the given indexes exist on a collection.
+// Failures are only logged so a missing index never stops the server booting.
+func CreateIndexes(collection *mongo.Collection, indexes []mongo.IndexModel) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	names, err := collection.Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		log.Printf("⚠️ [CreateIndexes] Failed to create indexes on '%s': %v\n", collection.Name(), err)
+		return
+	}
+	log.Printf("✅ [CreateIndexes] Indexes ready on '%s': %v\n", collection.Name(), names)
+}
+
 // 🔹 GetCollection
 
 // “Mujhe batao kaunsa database aur kaunsi collection chahiye — main de dunga.”
@@ -0,0 +1,191 @@
+package helpers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/hajimehoshi/go-mp3"
+)
+
+// ErrUnsupportedAudio is returned when the file is neither MP3 nor WAV
+var ErrUnsupportedAudio = errors.New("unsupported audio format (only MP3 and WAV are supported)")
+
+// pcmStream is a decoded audio stream that hands out one frame
+// (one sample per channel, scaled to [-1, 1]) at a time
+type pcmStream interface {
+	SampleRate() int
+	Channels() int
+	TotalFrames() int64
+	ReadFrame(frame []float64) error
+}
+
+// openPCMStream sniffs the first bytes of data and picks the right decoder
+func openPCMStream(data []byte) (pcmStream, error) {
+	switch {
+	case len(data) >= 12 && string(data[0:4]) == "RIFF" && string(data[8:12]) == "WAVE":
+		return newWAVStream(data)
+	case len(data) >= 3 && string(data[0:3]) == "ID3":
+		return newMP3Stream(data)
+	case len(data) >= 2 && data[0] == 0xFF && data[1]&0xE0 == 0xE0:
+		return newMP3Stream(data)
+	}
+	return nil, ErrUnsupportedAudio
+}
+
+// -------------------- MP3 --------------------
+
+// go-mp3 always decodes to 16-bit little-endian stereo
+type mp3Stream struct {
+	decoder *mp3.Decoder
+	reader  *bufio.Reader
+	buf     [4]byte
+}
+
+func newMP3Stream(data []byte) (*mp3Stream, error) {
+	decoder, err := mp3.NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("mp3 decode: %w", err)
+	}
+	return &mp3Stream{decoder: decoder, reader: bufio.NewReaderSize(decoder, 64*1024)}, nil
+}
+
+func (s *mp3Stream) SampleRate() int { return s.decoder.SampleRate() }
+
+func (s *mp3Stream) Channels() int { return 2 }
+
+func (s *mp3Stream) TotalFrames() int64 { return s.decoder.Length() / 4 }
+
+func (s *mp3Stream) ReadFrame(frame []float64) error {
+	if _, err := io.ReadFull(s.reader, s.buf[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return io.EOF
+		}
+		return err
+	}
+	frame[0] = float64(int16(binary.LittleEndian.Uint16(s.buf[0:2]))) / 32768
+	frame[1] = float64(int16(binary.LittleEndian.Uint16(s.buf[2:4]))) / 32768
+	return nil
+}
+
+// -------------------- WAV --------------------
+
+const (
+	wavFormatPCM        = 1
+	wavFormatFloat      = 3
+	wavFormatExtensible = 0xFFFE
+)
+
+type wavStream struct {
+	format        int
+	channels      int
+	sampleRate    int
+	bitsPerSample int
+	data          []byte
+	offset        int
+}
+
+func newWAVStream(data []byte) (*wavStream, error) {
+	s := &wavStream{}
+	pos := 12
+	haveFormat := false
+
+	for pos+8 <= len(data) {
+		chunkID := string(data[pos : pos+4])
+		chunkSize := int(binary.LittleEndian.Uint32(data[pos+4 : pos+8]))
+		body := pos + 8
+		end := body + chunkSize
+		if end > len(data) || chunkSize < 0 {
+			end = len(data)
+		}
+
+		switch chunkID {
+		case "fmt ":
+			if end-body < 16 {
+				return nil, errors.New("wav: fmt chunk too short")
+			}
+			s.format = int(binary.LittleEndian.Uint16(data[body : body+2]))
+			s.channels = int(binary.LittleEndian.Uint16(data[body+2 : body+4]))
+			s.sampleRate = int(binary.LittleEndian.Uint32(data[body+4 : body+8]))
+			s.bitsPerSample = int(binary.LittleEndian.Uint16(data[body+14 : body+16]))
+			if s.format == wavFormatExtensible && end-body >= 26 {
+				// The real format is the first two bytes of the SubFormat GUID
+				s.format = int(binary.LittleEndian.Uint16(data[body+24 : body+26]))
+			}
+			haveFormat = true
+		case "data":
+			if !haveFormat {
+				return nil, errors.New("wav: data chunk before fmt chunk")
+			}
+			s.data = data[body:end]
+		}
+
+		// Chunks are padded to an even size
+		pos = body + chunkSize + chunkSize%2
+	}
+
+	if !haveFormat || s.data == nil {
+		return nil, errors.New("wav: missing fmt or data chunk")
+	}
+	if s.channels < 1 || s.sampleRate < 1 {
+		return nil, errors.New("wav: invalid channel count or sample rate")
+	}
+
+	switch {
+	case s.format == wavFormatPCM && (s.bitsPerSample == 8 || s.bitsPerSample == 16 || s.bitsPerSample == 24 || s.bitsPerSample == 32):
+	case s.format == wavFormatFloat && (s.bitsPerSample == 32 || s.bitsPerSample == 64):
+	default:
+		return nil, fmt.Errorf("wav: unsupported encoding (format %d, %d bits)", s.format, s.bitsPerSample)
+	}
+
+	return s, nil
+}
+
+func (s *wavStream) SampleRate() int { return s.sampleRate }
+
+func (s *wavStream) Channels() int { return s.channels }
+
+func (s *wavStream) TotalFrames() int64 {
+	return int64(len(s.data) / (s.channels * s.bitsPerSample / 8))
+}
+
+func (s *wavStream) ReadFrame(frame []float64) error {
+	bytesPerSample := s.bitsPerSample / 8
+	if s.offset+bytesPerSample*s.channels > len(s.data) {
+		return io.EOF
+	}
+
+	for ch := 0; ch < s.channels; ch++ {
+		b := s.data[s.offset : s.offset+bytesPerSample]
+		s.offset += bytesPerSample
+
+		var v float64
+		switch {
+		case s.format == wavFormatFloat && s.bitsPerSample == 32:
+			v = float64(math.Float32frombits(binary.LittleEndian.Uint32(b)))
+		case s.format == wavFormatFloat:
+			v = math.Float64frombits(binary.LittleEndian.Uint64(b))
+		case s.bitsPerSample == 8:
+			v = (float64(b[0]) - 128) / 128
+		case s.bitsPerSample == 16:
+			v = float64(int16(binary.LittleEndian.Uint16(b))) / 32768
+		case s.bitsPerSample == 24:
+			n := int32(uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16)
+			if n&0x800000 != 0 {
+				n |= ^0xFFFFFF
+			}
+			v = float64(n) / 8388608
+		default:
+			v = float64(int32(binary.LittleEndian.Uint32(b))) / 2147483648
+		}
+
+		if ch < len(frame) {
+			frame[ch] = v
+		}
+	}
+	return nil
+}
@@ -8,7 +8,7 @@ import (
 
 
 
-func checkUserType(c *gin.Context, role string) (err error) {
+func CheckUserType(c *gin.Context, role string) (err error) {
 	userType:= c.GetString("user_type")
 	err= nil
 	if userType!=role{
@@ -29,7 +29,7 @@ func MatchUserTypeToUid(c *gin.Context, userId string) (err error) {
 			err= errors.New("unauthorized access to this resource")
 			return err
 		}
-		err = checkUserType(c,userType)
+		err = CheckUserType(c,userType)
 		return err
 
 
@@ -2,9 +2,15 @@ package helpers
 
 import (
     "context"
+    "crypto/rand"
+    "encoding/hex"
+    "errors"
     "log"
     "mime/multipart"
     "os"
+    "path"
+    "regexp"
+    "strings"
 
     "github.com/cloudinary/cloudinary-go/v2"
     "github.com/cloudinary/cloudinary-go/v2/api/uploader"
@@ -23,11 +29,16 @@ func UploadFile(file multipart.File, fileHeader *multipart.FileHeader, folder st
         return "", err
     }
 
+    publicID, err := uniquePublicID(fileHeader.Filename)
+    if err != nil {
+        return "", err
+    }
+
     // Correct upload using io.Reader (file stream)
     uploadResult, err := cld.Upload.Upload(context.Background(), file, uploader.UploadParams{
         Folder:       folder,
         ResourceType: "video", // needed for mp3/mp4 uploads
-        PublicID:     fileHeader.Filename,
+        PublicID:     publicID,
     })
 
     if err != nil {
@@ -37,3 +48,66 @@ func UploadFile(file multipart.File, fileHeader *multipart.FileHeader, folder st
 
     return uploadResult.SecureURL, nil
 }
+
+// uniquePublicID names an upload after its file plus a random suffix, so two
+// uploads called "cover.jpg" never share (and overwrite or delete) one asset
+func uniquePublicID(filename string) (string, error) {
+    suffix := make([]byte, 8)
+    if _, err := rand.Read(suffix); err != nil {
+        return "", err
+    }
+
+    name := Slugify(strings.TrimSuffix(filename, path.Ext(filename)))
+    if name == "" {
+        name = "file"
+    }
+    return name + "-" + hex.EncodeToString(suffix), nil
+}
+
+// Matches the "v1712345678/" version segment in Cloudinary delivery URLs
+var cloudinaryVersionPattern = regexp.MustCompile(`^v\d+/`)
+
+// DeleteFile removes a file previously returned by UploadFile
+func DeleteFile(fileURL string) error {
+    publicID, err := cloudinaryPublicID(fileURL)
+    if err != nil {
+        return err
+    }
+
+    cld, err := cloudinary.NewFromURL(os.Getenv("CLOUDINARY_URL"))
+    if err != nil {
+        log.Println("Cloudinary init error:", err)
+        return err
+    }
+
+    result, err := cld.Upload.Destroy(context.Background(), uploader.DestroyParams{
+        PublicID:     publicID,
+        ResourceType: "video", // UploadFile stores everything as "video"
+    })
+    if err != nil {
+        log.Println("Cloudinary delete error:", err)
+        return err
+    }
+    if result.Error.Message != "" {
+        return errors.New(result.Error.Message)
+    }
+
+    return nil
+}
+
+// cloudinaryPublicID turns
+// https://res.cloudinary.com/<cloud>/video/upload/v123/songs/track.mp3 into "songs/track"
+func cloudinaryPublicID(fileURL string) (string, error) {
+    idx := strings.Index(fileURL, "/upload/")
+    if idx == -1 {
+        return "", errors.New("not a Cloudinary upload URL")
+    }
+
+    publicID := cloudinaryVersionPattern.ReplaceAllString(fileURL[idx+len("/upload/"):], "")
+    publicID = strings.TrimSuffix(publicID, path.Ext(publicID))
+    if publicID == "" {
+        return "", errors.New("not a Cloudinary upload URL")
+    }
+
+    return publicID, nil
+}
@@ -0,0 +1,43 @@
+package helpers
+
+import (
+	"encoding/base64"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// pageCursor is the position after the last item of a page: the value of the
+// sort field plus _id to break ties. Sort names the ordering it belongs to.
+type pageCursor struct {
+	Sort  string             `bson:"s"`
+	Value interface{}        `bson:"v"`
+	ID    primitive.ObjectID `bson:"id"`
+}
+
+// EncodeCursor turns a page position into an opaque, URL-safe string.
+// BSON keeps the value's type, so dates and numbers compare correctly when decoded.
+func EncodeCursor(sort string, value interface{}, id primitive.ObjectID) (string, error) {
+	data, err := bson.Marshal(pageCursor{Sort: sort, Value: value, ID: id})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor reads a cursor made by EncodeCursor for the same sort
+func DecodeCursor(cursor string, sort string) (interface{}, primitive.ObjectID, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, primitive.NilObjectID, ErrInvalidCursor
+	}
+
+	var decoded pageCursor
+	if err := bson.Unmarshal(data, &decoded); err != nil || decoded.Sort != sort || decoded.ID.IsZero() {
+		return nil, primitive.NilObjectID, ErrInvalidCursor
+	}
+	return decoded.Value, decoded.ID, nil
+}
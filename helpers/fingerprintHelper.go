@@ -0,0 +1,120 @@
+package helpers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"mime/multipart"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Anything inside brackets is usually noise like "(Official Audio)" or "[Lyrical]"
+var bracketPattern = regexp.MustCompile(`[\(\[\{][^\)\]\}]*[\)\]\}]`)
+
+// Cut the title at "feat." so featured artists don't change the fingerprint
+var featPattern = regexp.MustCompile(`(?i)\s+(feat\.?|ft\.?|featuring)\s+.*$`)
+
+// Where the featured artists start in an artist line ("A feat. B", "A (ft. B)")
+var featuredArtistPattern = regexp.MustCompile(`(?i)\s*[\(\[]?\s*\b(feat\.?|ft\.?|featuring)(\s+|$)`)
+
+// Separators used between artist names ("A, B & C", "A x B", "A feat. B")
+var artistSeparatorPattern = regexp.MustCompile(`(?i)\s*(,|&|\+|/|\bx\b|\band\b|\bfeat\.?|\bft\.?|\bfeaturing\b)\s*`)
+
+// HashFile returns the hex encoded SHA-256 of an uploaded file.
+// The file is rewound afterwards so it can still be uploaded.
+func HashFile(file multipart.File) (string, error) {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", err
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// SongFingerprint builds a normalized "title|artists" key, so that
+// "Tere Bina (Official Audio)" by "A.R. Rahman & Chinmayi" and
+// "tere bina" by "Chinmayi, AR Rahman" end up with the same fingerprint.
+// It is "" when the title or the artists normalize to nothing, since such
+// songs can't be told apart.
+func SongFingerprint(title string, artist string) string {
+	cleanTitle := bracketPattern.ReplaceAllString(title, " ")
+	cleanTitle = featPattern.ReplaceAllString(cleanTitle, "")
+
+	names := SplitArtistNames(artist)
+	artists := make([]string, 0, len(names))
+	for _, name := range names {
+		if n := normalizeFingerprintText(name); n != "" {
+			artists = append(artists, n)
+		}
+	}
+	sort.Strings(artists)
+
+	normalizedTitle := normalizeFingerprintText(cleanTitle)
+	if normalizedTitle == "" || len(artists) == 0 {
+		return ""
+	}
+	return normalizedTitle + "|" + strings.Join(artists, ",")
+}
+
+// SplitArtistNames splits a free-text artist field like "A, B & C feat. D"
+// into trimmed individual names
+func SplitArtistNames(artist string) []string {
+	var names []string
+	for _, name := range artistSeparatorPattern.Split(artist, -1) {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// SplitFeaturedArtists splits an artist line at "feat."/"ft."/"featuring"
+// into the main artists and the featured ones, without the brackets
+func SplitFeaturedArtists(artist string) (main string, featured string) {
+	loc := featuredArtistPattern.FindStringIndex(artist)
+	if loc == nil {
+		return strings.TrimSpace(artist), ""
+	}
+	return strings.TrimSpace(artist[:loc[0]]), strings.Trim(artist[loc[1]:], " )]")
+}
+
+// DurationsMatch reports whether two durations (in seconds) are close enough
+// to be the same recording. An unknown duration (0) matches nothing.
+func DurationsMatch(a int, b int) bool {
+	if a == 0 || b == 0 {
+		return false
+	}
+	diff := a - b
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= 3
+}
+
+// normalizeFingerprintText lowercases and keeps only letters and digits,
+// collapsing everything else into single spaces. Works for any script.
+func normalizeFingerprintText(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Mc, r):
+			b.WriteRune(r)
+		case r == '.' || r == '\'':
+			// "A.R." -> "ar", "don't" -> "dont"
+		default:
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
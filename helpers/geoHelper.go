@@ -0,0 +1,112 @@
+package helpers
+
+import (
+	"net"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oschwald/maxminddb-golang"
+)
+
+var geoDB *maxminddb.Reader
+
+// Proxies allowed to tell us the client's country; see SetTrustedProxies
+var trustedProxies []*net.IPNet
+
+// InitGeoIP opens the GeoIP country database at GEOIP_DB_PATH (a MaxMind
+// .mmdb file such as GeoLite2-Country). Without one, countries come only from
+// the COUNTRY_HEADER set by a trusted proxy or CDN.
+func InitGeoIP() error {
+	path := os.Getenv("GEOIP_DB_PATH")
+	if path == "" {
+		return nil
+	}
+	db, err := maxminddb.Open(path)
+	if err != nil {
+		return err
+	}
+	geoDB = db
+	return nil
+}
+
+// SetTrustedProxies sets the proxies (IPs or CIDRs, the same list given to
+// gin's SetTrustedProxies) whose COUNTRY_HEADER is believed. Anyone else can
+// send that header, so from them it is ignored.
+func SetTrustedProxies(proxies []string) error {
+	nets := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			if ip := net.ParseIP(proxy); ip != nil && ip.To4() != nil {
+				proxy += "/32"
+			} else {
+				proxy += "/128"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return err
+		}
+		nets = append(nets, ipNet)
+	}
+	trustedProxies = nets
+	return nil
+}
+
+// fromTrustedProxy reports whether the request came straight from a trusted proxy
+func fromTrustedProxy(c *gin.Context) bool {
+	ip := net.ParseIP(c.RemoteIP())
+	if ip == nil {
+		return false
+	}
+	for _, ipNet := range trustedProxies {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// RequestCountry resolves the ISO 3166 alpha-2 code of the country a request
+// comes from: the COUNTRY_HEADER (e.g. CF-IPCountry) if set, valid and sent
+// by a trusted proxy, then the GeoIP database. It returns "" when neither knows.
+func RequestCountry(c *gin.Context) string {
+	if header := os.Getenv("COUNTRY_HEADER"); header != "" && fromTrustedProxy(c) {
+		if code, ok := NormalizeCountryCode(c.GetHeader(header)); ok {
+			return code
+		}
+	}
+
+	if geoDB == nil {
+		return ""
+	}
+	ip := net.ParseIP(c.ClientIP())
+	if ip == nil {
+		return ""
+	}
+	var record struct {
+		Country struct {
+			ISOCode string `maxminddb:"iso_code"`
+		} `maxminddb:"country"`
+	}
+	if err := geoDB.Lookup(ip, &record); err != nil {
+		return ""
+	}
+	code, _ := NormalizeCountryCode(record.Country.ISOCode)
+	return code
+}
+
+// NormalizeCountryCode upper-cases a two-letter country code. The "XX" and
+// "T1" placeholders CDNs send for unknown and Tor traffic are not countries.
+func NormalizeCountryCode(code string) (string, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 2 || code == "XX" || code == "T1" {
+		return "", false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return "", false
+		}
+	}
+	return code, true
+}
@@ -0,0 +1,206 @@
+package helpers
+
+import (
+	"io"
+	"math"
+)
+
+// WaveformPoints is how many peaks we keep per song for the player UI
+const WaveformPoints = 800
+
+// TargetLoudnessLUFS is the level the player normalises every track to
+const TargetLoudnessLUFS = -14.0
+
+// AudioAnalysis is the result of decoding a song once
+type AudioAnalysis struct {
+	Peaks        []float64 // WaveformPoints values between 0 and 1
+	LoudnessLUFS float64   // integrated loudness (ITU-R BS.1770)
+	Duration     float64   // seconds
+}
+
+// AnalyzeAudio decodes an MP3 or WAV file and computes its waveform peaks
+// and integrated loudness in a single streaming pass.
+func AnalyzeAudio(data []byte) (*AudioAnalysis, error) {
+	stream, err := openPCMStream(data)
+	if err != nil {
+		return nil, err
+	}
+
+	channels := stream.Channels()
+	sampleRate := stream.SampleRate()
+	totalFrames := stream.TotalFrames()
+	if totalFrames <= 0 {
+		totalFrames = 1
+	}
+
+	peaks := make([]float64, WaveformPoints)
+
+	// K-weighting filters, one pair per channel
+	shelf := make([]*biquad, channels)
+	highPass := make([]*biquad, channels)
+	for ch := 0; ch < channels; ch++ {
+		shelf[ch], highPass[ch] = kWeightingFilters(float64(sampleRate))
+	}
+
+	// Energy is collected in 100ms steps; a 400ms gating block is 4 steps
+	stepFrames := int64(sampleRate / 10)
+	var stepEnergies []float64
+	var stepSum float64
+	var stepCount int64
+
+	frame := make([]float64, channels)
+	var frames int64
+
+	for {
+		if err := stream.ReadFrame(frame); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+
+		bucket := int(frames * WaveformPoints / totalFrames)
+		if bucket >= WaveformPoints {
+			bucket = WaveformPoints - 1
+		}
+
+		for ch := 0; ch < channels; ch++ {
+			if abs := math.Abs(frame[ch]); abs > peaks[bucket] {
+				peaks[bucket] = abs
+			}
+			weighted := highPass[ch].process(shelf[ch].process(frame[ch]))
+			stepSum += weighted * weighted
+		}
+
+		stepCount++
+		if stepCount == stepFrames {
+			stepEnergies = append(stepEnergies, stepSum/float64(stepFrames))
+			stepSum = 0
+			stepCount = 0
+		}
+		frames++
+	}
+
+	if frames == 0 {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	for i, p := range peaks {
+		peaks[i] = math.Round(math.Min(p, 1)*1000) / 1000
+	}
+
+	return &AudioAnalysis{
+		Peaks:        peaks,
+		LoudnessLUFS: integratedLoudness(stepEnergies),
+		Duration:     float64(frames) / float64(sampleRate),
+	}, nil
+}
+
+// integratedLoudness applies the BS.1770 absolute (-70 LUFS) and
+// relative (-10 LU) gates to overlapping 400ms blocks
+func integratedLoudness(stepEnergies []float64) float64 {
+	var blocks []float64
+	for i := 0; i+4 <= len(stepEnergies); i++ {
+		blocks = append(blocks, (stepEnergies[i]+stepEnergies[i+1]+stepEnergies[i+2]+stepEnergies[i+3])/4)
+	}
+
+	// Shorter than one block: just measure what we have
+	if len(blocks) == 0 {
+		var sum float64
+		for _, e := range stepEnergies {
+			sum += e
+		}
+		if len(stepEnergies) == 0 || sum == 0 {
+			return -70
+		}
+		return roundLoudness(energyToLUFS(sum / float64(len(stepEnergies))))
+	}
+
+	gatedMean := func(threshold float64) (float64, int) {
+		var sum float64
+		var n int
+		for _, z := range blocks {
+			if energyToLUFS(z) > threshold {
+				sum += z
+				n++
+			}
+		}
+		if n == 0 {
+			return 0, 0
+		}
+		return sum / float64(n), n
+	}
+
+	absoluteMean, n := gatedMean(-70)
+	if n == 0 {
+		return -70
+	}
+
+	relativeGate := energyToLUFS(absoluteMean) - 10
+	mean, n := gatedMean(relativeGate)
+	if n == 0 {
+		return -70
+	}
+
+	return roundLoudness(energyToLUFS(mean))
+}
+
+func energyToLUFS(z float64) float64 {
+	if z <= 0 {
+		return math.Inf(-1)
+	}
+	return -0.691 + 10*math.Log10(z)
+}
+
+func roundLoudness(v float64) float64 {
+	return math.Round(v*10) / 10
+}
+
+// biquad is a direct form I IIR filter
+type biquad struct {
+	b0, b1, b2, a1, a2 float64
+	x1, x2, y1, y2     float64
+}
+
+func (f *biquad) process(x float64) float64 {
+	y := f.b0*x + f.b1*f.x1 + f.b2*f.x2 - f.a1*f.y1 - f.a2*f.y2
+	f.x2, f.x1 = f.x1, x
+	f.y2, f.y1 = f.y1, y
+	return y
+}
+
+// kWeightingFilters returns the BS.1770 pre-filter (high shelf) and RLB
+// high-pass filter designed for any sample rate, as done in libebur128
+func kWeightingFilters(sampleRate float64) (*biquad, *biquad) {
+	f0 := 1681.974450955533
+	gain := 3.999843853973347
+	q := 0.7071752369554196
+
+	k := math.Tan(math.Pi * f0 / sampleRate)
+	vh := math.Pow(10, gain/20)
+	vb := math.Pow(vh, 0.4996667741545416)
+	a0 := 1 + k/q + k*k
+
+	shelf := &biquad{
+		b0: (vh + vb*k/q + k*k) / a0,
+		b1: 2 * (k*k - vh) / a0,
+		b2: (vh - vb*k/q + k*k) / a0,
+		a1: 2 * (k*k - 1) / a0,
+		a2: (1 - k/q + k*k) / a0,
+	}
+
+	f0 = 38.13547087602444
+	q = 0.5003270373238773
+	k = math.Tan(math.Pi * f0 / sampleRate)
+	a0 = 1 + k/q + k*k
+
+	highPass := &biquad{
+		b0: 1,
+		b1: -2,
+		b2: 1,
+		a1: 2 * (k*k - 1) / a0,
+		a2: (1 - k/q + k*k) / a0,
+	}
+
+	return shelf, highPass
+}
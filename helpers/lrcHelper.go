@@ -0,0 +1,120 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidLRC = errors.New("invalid LRC lyrics")
+
+// LRCLine is one line of time-synced lyrics, TimeMs from the start of the song
+type LRCLine struct {
+	TimeMs int64
+	Text   string
+}
+
+// [01:23.45], [01:23.456], [01:23] and the occasional [01:23:45]
+var lrcTimestamp = regexp.MustCompile(`^\[(\d{1,3}):(\d{1,2})(?:[.:](\d{1,3}))?\]`)
+
+// [ar:Artist], [offset:+250] and other ID tags
+var lrcTag = regexp.MustCompile(`^\[([a-zA-Z#]+):(.*)\]$`)
+
+// ParseLRC parses time-synced lyrics. A line may carry several timestamps
+// ("[00:12.00][01:30.00]chorus"), tag lines are skipped except [offset:],
+// and the result is sorted by time. Anything else that isn't blank is an error.
+func ParseLRC(text string) ([]LRCLine, error) {
+	var lines []LRCLine
+	var offsetMs int64
+
+	for i, raw := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" {
+			continue
+		}
+
+		var times []int64
+		for {
+			match := lrcTimestamp.FindStringSubmatch(line)
+			if match == nil {
+				break
+			}
+			ms, err := lrcTimeMs(match[1], match[2], match[3])
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidLRC, i+1, err)
+			}
+			times = append(times, ms)
+			line = line[len(match[0]):]
+		}
+
+		if len(times) == 0 {
+			tag := lrcTag.FindStringSubmatch(line)
+			if tag == nil {
+				return nil, fmt.Errorf("%w: line %d has no timestamp", ErrInvalidLRC, i+1)
+			}
+			if strings.EqualFold(tag[1], "offset") {
+				offset, err := strconv.ParseInt(strings.TrimSpace(tag[2]), 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("%w: line %d: offset must be milliseconds", ErrInvalidLRC, i+1)
+				}
+				offsetMs = offset
+			}
+			continue
+		}
+
+		for _, ms := range times {
+			lines = append(lines, LRCLine{TimeMs: ms, Text: strings.TrimSpace(line)})
+		}
+	}
+
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("%w: no timed lines", ErrInvalidLRC)
+	}
+
+	// A positive offset shows lyrics earlier
+	for i := range lines {
+		lines[i].TimeMs -= offsetMs
+		if lines[i].TimeMs < 0 {
+			lines[i].TimeMs = 0
+		}
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].TimeMs < lines[j].TimeMs })
+	return lines, nil
+}
+
+func lrcTimeMs(minutes, seconds, fraction string) (int64, error) {
+	m, _ := strconv.ParseInt(minutes, 10, 64)
+	s, _ := strconv.ParseInt(seconds, 10, 64)
+	if s >= 60 {
+		return 0, fmt.Errorf("seconds must be under 60")
+	}
+
+	var ms int64
+	if fraction != "" {
+		// .4 is 400ms, .45 is 450ms, .456 is 456ms
+		f, _ := strconv.ParseInt(fraction, 10, 64)
+		for i := len(fraction); i < 3; i++ {
+			f *= 10
+		}
+		ms = f
+	}
+	return (m*60+s)*1000 + ms, nil
+}
+
+// FormatLRCTime renders milliseconds as an LRC timestamp body, "mm:ss.xx"
+func FormatLRCTime(ms int64) string {
+	return fmt.Sprintf("%02d:%02d.%02d", ms/60000, ms/1000%60, ms%1000/10)
+}
+
+// LRCPlainText joins synced lines into plain lyrics
+func LRCPlainText(lines []LRCLine) string {
+	texts := make([]string, 0, len(lines))
+	for _, line := range lines {
+		texts = append(texts, line.Text)
+	}
+	return strings.Join(texts, "\n")
+}
@@ -0,0 +1,76 @@
+package helpers
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseLRC(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []LRCLine
+	}{
+		{"tenths", "[01:23.4]line", []LRCLine{{83400, "line"}}},
+		{"hundredths", "[01:23.45]line", []LRCLine{{83450, "line"}}},
+		{"milliseconds", "[01:23.456]line", []LRCLine{{83456, "line"}}},
+		{"no fraction", "[01:23]line", []LRCLine{{83000, "line"}}},
+		{"colon before fraction", "[01:23:45]line", []LRCLine{{83450, "line"}}},
+		{"several timestamps, sorted", "[00:02.00][00:01.00]chorus", []LRCLine{{1000, "chorus"}, {2000, "chorus"}}},
+		{"tags skipped, blank lines ignored", "[ar:Artist]\n\n[ti:Title]\r\n[00:05.00] verse ", []LRCLine{{5000, "verse"}}},
+		{"positive offset shows lyrics earlier, clamped at 0", "[offset:+500]\n[00:00.20]a\n[00:01.00]b", []LRCLine{{0, "a"}, {500, "b"}}},
+		{"negative offset shows lyrics later", "[offset:-250]\n[00:01.00]a", []LRCLine{{1250, "a"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLRC(tt.text)
+			if err != nil {
+				t.Fatalf("ParseLRC(%q) error: %v", tt.text, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseLRC(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLRCInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"empty", ""},
+		{"only tags", "[ar:Artist]\n[ti:Title]"},
+		{"untimed line", "[00:01.00]a\nno timestamp"},
+		{"seconds over 59", "[00:60.00]a"},
+		{"offset not a number", "[offset:soon]\n[00:01.00]a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseLRC(tt.text); !errors.Is(err, ErrInvalidLRC) {
+				t.Errorf("ParseLRC(%q) error = %v, want ErrInvalidLRC", tt.text, err)
+			}
+		})
+	}
+}
+
+func TestFormatLRCTime(t *testing.T) {
+	tests := []struct {
+		ms   int64
+		want string
+	}{
+		{0, "00:00.00"},
+		{83450, "01:23.45"},
+		{83456, "01:23.45"},
+		{600000, "10:00.00"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatLRCTime(tt.ms); got != tt.want {
+			t.Errorf("FormatLRCTime(%d) = %q, want %q", tt.ms, got, tt.want)
+		}
+	}
+}
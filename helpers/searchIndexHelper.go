@@ -0,0 +1,183 @@
+package helpers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/custom"
+	"github.com/blevesearch/bleve/v2/analysis/token/lowercase"
+	"github.com/blevesearch/bleve/v2/analysis/tokenizer/whitespace"
+	"github.com/blevesearch/bleve/v2/search/query"
+)
+
+// Text is normalized with NormalizeSearchText before it reaches Bleve,
+// so the analyzer only has to split on spaces
+const songIndexAnalyzer = "normalized"
+
+// Field boosts: a hit on the title counts most
+var songIndexFields = map[string]float64{
+	"title":  3,
+	"artist": 2,
+	"album":  1,
+}
+
+var songIndex bleve.Index
+
+var ErrSongIndexNotReady = errors.New("song search index is not ready")
+
+// SearchableSong is the part of a song that goes into the search index
+type SearchableSong struct {
+	SongID string
+	Title  string
+	Artist string
+	Album  string
+}
+
+// SongSearchResult is one song ID matched by the index, best match first
+type SongSearchResult struct {
+	SongID string
+	Score  float64
+}
+
+// InitSongIndex creates the in-memory song index. It starts empty and is
+// filled from the songs collection by the caller.
+func InitSongIndex() error {
+	indexMapping := bleve.NewIndexMapping()
+	err := indexMapping.AddCustomAnalyzer(songIndexAnalyzer, map[string]interface{}{
+		"type":          custom.Name,
+		"tokenizer":     whitespace.Name,
+		"token_filters": []string{lowercase.Name},
+	})
+	if err != nil {
+		return err
+	}
+	indexMapping.DefaultAnalyzer = songIndexAnalyzer
+
+	index, err := bleve.NewMemOnly(indexMapping)
+	if err != nil {
+		return err
+	}
+	songIndex = index
+	return nil
+}
+
+// IndexSongs adds or replaces songs in the index
+func IndexSongs(songs []SearchableSong) error {
+	if songIndex == nil {
+		return ErrSongIndexNotReady
+	}
+
+	batch := songIndex.NewBatch()
+	for _, song := range songs {
+		err := batch.Index(song.SongID, map[string]interface{}{
+			"title":  NormalizeSearchText(song.Title),
+			"artist": NormalizeSearchText(song.Artist),
+			"album":  NormalizeSearchText(song.Album),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return songIndex.Batch(batch)
+}
+
+// RemoveSongsFromIndex drops songs from the index; unknown IDs are ignored
+func RemoveSongsFromIndex(songIDs ...string) error {
+	if songIndex == nil {
+		return ErrSongIndexNotReady
+	}
+
+	batch := songIndex.NewBatch()
+	for _, songID := range songIDs {
+		batch.Delete(songID)
+	}
+	return songIndex.Batch(batch)
+}
+
+// IndexedSongIDs lists every song ID currently in the index
+func IndexedSongIDs() ([]string, error) {
+	if songIndex == nil {
+		return nil, ErrSongIndexNotReady
+	}
+
+	count, err := songIndex.DocCount()
+	if err != nil || count == 0 {
+		return nil, err
+	}
+
+	result, err := songIndex.Search(bleve.NewSearchRequestOptions(bleve.NewMatchAllQuery(), int(count), 0, false))
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		ids = append(ids, hit.ID)
+	}
+	return ids, nil
+}
+
+// SearchSongIndex finds songs whose title, artist or album match every word of
+// the query within a small edit distance, after transliteration and spelling
+// normalization. The last word also matches as a prefix, for search-as-you-type.
+func SearchSongIndex(text string, limit int) ([]SongSearchResult, error) {
+	if songIndex == nil {
+		return nil, ErrSongIndexNotReady
+	}
+
+	words := strings.Fields(NormalizeSearchText(text))
+	if len(words) == 0 {
+		return []SongSearchResult{}, nil
+	}
+
+	wordQueries := make([]query.Query, 0, len(words))
+	for i, word := range words {
+		fieldQueries := make([]query.Query, 0, len(songIndexFields)*2)
+		for field, boost := range songIndexFields {
+			fuzzy := bleve.NewFuzzyQuery(word)
+			fuzzy.SetField(field)
+			fuzzy.SetFuzziness(fuzzinessFor(word))
+			fuzzy.SetBoost(boost)
+			fieldQueries = append(fieldQueries, fuzzy)
+
+			// Exact hits outrank fuzzy ones
+			exact := bleve.NewTermQuery(word)
+			exact.SetField(field)
+			exact.SetBoost(boost * 2)
+			fieldQueries = append(fieldQueries, exact)
+
+			if i == len(words)-1 {
+				prefix := bleve.NewPrefixQuery(word)
+				prefix.SetField(field)
+				prefix.SetBoost(boost)
+				fieldQueries = append(fieldQueries, prefix)
+			}
+		}
+		wordQueries = append(wordQueries, bleve.NewDisjunctionQuery(fieldQueries...))
+	}
+
+	request := bleve.NewSearchRequestOptions(bleve.NewConjunctionQuery(wordQueries...), limit, 0, false)
+	result, err := songIndex.Search(request)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]SongSearchResult, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		results = append(results, SongSearchResult{SongID: hit.ID, Score: hit.Score})
+	}
+	return results, nil
+}
+
+// Short words tolerate fewer typos, otherwise "tu" would match half the catalogue
+func fuzzinessFor(word string) int {
+	switch n := len([]rune(word)); {
+	case n <= 2:
+		return 0
+	case n <= 5:
+		return 1
+	default:
+		return 2
+	}
+}
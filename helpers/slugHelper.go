@@ -0,0 +1,25 @@
+package helpers
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Slugify turns a display name into a URL-safe key, so "Hip Hop", "hip-hop"
+// and " HIP  HOP " all become "hip-hop". Letters outside ASCII are kept.
+func Slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Mc, r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			dash = false
+			b.WriteRune(r)
+			continue
+		}
+		dash = true
+	}
+	return b.String()
+}
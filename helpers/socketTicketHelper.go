@@ -0,0 +1,61 @@
+package helpers
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"sync"
+	"time"
+)
+
+// SocketTicketTTL is how long a socket ticket can wait before it's redeemed
+const SocketTicketTTL = 30 * time.Second
+
+type socketTicket struct {
+	userID    string
+	userType  string
+	expiresAt time.Time
+}
+
+var (
+	socketTicketsMu sync.Mutex
+	socketTickets   = make(map[string]socketTicket)
+)
+
+// IssueSocketTicket returns a short-lived, single-use ticket standing in for
+// the user's token on a WebSocket handshake. Browsers can't set headers on
+// the handshake, and a JWT in the URL would end up in access logs.
+func IssueSocketTicket(userID string, userType string) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	ticket := base64.RawURLEncoding.EncodeToString(raw)
+
+	socketTicketsMu.Lock()
+	defer socketTicketsMu.Unlock()
+
+	now := time.Now()
+	for key, issued := range socketTickets {
+		if now.After(issued.expiresAt) {
+			delete(socketTickets, key)
+		}
+	}
+	socketTickets[ticket] = socketTicket{userID: userID, userType: userType, expiresAt: now.Add(SocketTicketTTL)}
+	return ticket, nil
+}
+
+// RedeemSocketTicket uses up a ticket and returns who it was issued to
+func RedeemSocketTicket(ticket string) (userID string, userType string, ok bool) {
+	socketTicketsMu.Lock()
+	defer socketTicketsMu.Unlock()
+
+	issued, found := socketTickets[ticket]
+	if !found {
+		return "", "", false
+	}
+	delete(socketTickets, ticket)
+	if time.Now().After(issued.expiresAt) {
+		return "", "", false
+	}
+	return issued.userID, issued.userType, true
+}
@@ -0,0 +1,190 @@
+package helpers
+
+import (
+	"strings"
+	"unicode"
+)
+
+// indicScript holds the romanisation table for one Indic script
+type indicScript struct {
+	vowels     map[rune]string // independent vowels
+	consonants map[rune]string // carry an inherent "a" unless a matra or virama follows
+	matras     map[rune]string // dependent vowel signs
+	nasals     map[rune]string // anusvara, chandrabindu, tippi, visarga...
+	virama     rune
+	ignored    map[rune]bool // nukta, addak...
+	zero       rune          // first digit of the script
+}
+
+var devanagari = indicScript{
+	vowels: map[rune]string{
+		'अ': "a", 'आ': "aa", 'इ': "i", 'ई': "ee", 'उ': "u", 'ऊ': "oo", 'ऋ': "ri",
+		'ए': "e", 'ऐ': "ai", 'ओ': "o", 'औ': "au", 'ऑ': "o", 'ऍ': "e",
+	},
+	consonants: map[rune]string{
+		'क': "k", 'ख': "kh", 'ग': "g", 'घ': "gh", 'ङ': "n",
+		'च': "ch", 'छ': "chh", 'ज': "j", 'झ': "jh", 'ञ': "n",
+		'ट': "t", 'ठ': "th", 'ड': "d", 'ढ': "dh", 'ण': "n",
+		'त': "t", 'थ': "th", 'द': "d", 'ध': "dh", 'न': "n",
+		'प': "p", 'फ': "ph", 'ब': "b", 'भ': "bh", 'म': "m",
+		'य': "y", 'र': "r", 'ल': "l", 'ळ': "l", 'व': "v",
+		'श': "sh", 'ष': "sh", 'स': "s", 'ह': "h",
+		'\u0958': "q", '\u0959': "kh", '\u095A': "g", '\u095B': "z", '\u095C': "r", '\u095D': "rh", '\u095E': "f", '\u095F': "y", // nukta forms
+	},
+	matras: map[rune]string{
+		'ा': "aa", 'ि': "i", 'ी': "ee", 'ु': "u", 'ू': "oo", 'ृ': "ri",
+		'े': "e", 'ै': "ai", 'ो': "o", 'ौ': "au", 'ॉ': "o", 'ॅ': "e",
+	},
+	nasals:  map[rune]string{'ं': "n", 'ँ': "n", 'ः': "h"},
+	virama:  '्',
+	ignored: map[rune]bool{'़': true},
+	zero:    '०',
+}
+
+var gurmukhi = indicScript{
+	vowels: map[rune]string{
+		'ਅ': "a", 'ਆ': "aa", 'ਇ': "i", 'ਈ': "ee", 'ਉ': "u", 'ਊ': "oo",
+		'ਏ': "e", 'ਐ': "ai", 'ਓ': "o", 'ਔ': "au",
+	},
+	consonants: map[rune]string{
+		'ਕ': "k", 'ਖ': "kh", 'ਗ': "g", 'ਘ': "gh", 'ਙ': "n",
+		'ਚ': "ch", 'ਛ': "chh", 'ਜ': "j", 'ਝ': "jh", 'ਞ': "n",
+		'ਟ': "t", 'ਠ': "th", 'ਡ': "d", 'ਢ': "dh", 'ਣ': "n",
+		'ਤ': "t", 'ਥ': "th", 'ਦ': "d", 'ਧ': "dh", 'ਨ': "n",
+		'ਪ': "p", 'ਫ': "ph", 'ਬ': "b", 'ਭ': "bh", 'ਮ': "m",
+		'ਯ': "y", 'ਰ': "r", 'ਲ': "l", '\u0A33': "l", 'ਵ': "v",
+		'\u0A36': "sh", 'ਸ': "s", 'ਹ': "h",
+		'\u0A59': "kh", '\u0A5A': "g", '\u0A5B': "z", 'ੜ': "r", '\u0A5E': "f", // nukta forms
+	},
+	matras: map[rune]string{
+		'ਾ': "aa", 'ਿ': "i", 'ੀ': "ee", 'ੁ': "u", 'ੂ': "oo",
+		'ੇ': "e", 'ੈ': "ai", 'ੋ': "o", 'ੌ': "au",
+	},
+	nasals:  map[rune]string{'ਂ': "n", 'ੰ': "n", 'ਃ': "h"},
+	virama:  '੍',
+	ignored: map[rune]bool{'਼': true, 'ੱ': true},
+	zero:    '੦',
+}
+
+func scriptOf(r rune) *indicScript {
+	switch {
+	case r >= 0x0900 && r <= 0x097F:
+		return &devanagari
+	case r >= 0x0A00 && r <= 0x0A7F:
+		return &gurmukhi
+	}
+	return nil
+}
+
+// Transliterate romanises Devanagari and Gurmukhi text the way people type it
+// ("तेरे बिना" → "tere binaa"). Other characters pass through unchanged.
+// The inherent "a" is dropped at the end of a word, as in spoken Hindi and Punjabi.
+func Transliterate(text string) string {
+	var out strings.Builder
+	pending := false // last consonant still owes its inherent "a"
+	wordLen := 0     // Indic characters in the current word
+
+	flush := func(endOfWord bool) {
+		if pending && (!endOfWord || wordLen == 1) {
+			out.WriteString("a")
+		}
+		pending = false
+	}
+
+	for _, r := range text {
+		script := scriptOf(r)
+		if script == nil {
+			flush(true)
+			wordLen = 0
+			out.WriteRune(r)
+			continue
+		}
+
+		if roman, ok := script.consonants[r]; ok {
+			flush(false)
+			out.WriteString(roman)
+			pending = true
+			wordLen++
+			continue
+		}
+
+		switch {
+		case script.matras[r] != "":
+			out.WriteString(script.matras[r])
+			pending = false
+		case r == script.virama:
+			pending = false
+		case script.nasals[r] != "":
+			flush(false)
+			out.WriteString(script.nasals[r])
+		case script.vowels[r] != "":
+			flush(false)
+			out.WriteString(script.vowels[r])
+		case script.ignored[r]:
+		case r >= script.zero && r <= script.zero+9:
+			flush(true)
+			wordLen = 0
+			out.WriteRune('0' + (r - script.zero))
+			continue
+		default:
+			// Dandas and other punctuation end the word
+			flush(true)
+			wordLen = 0
+			out.WriteRune(' ')
+			continue
+		}
+		wordLen++
+	}
+	flush(true)
+
+	return out.String()
+}
+
+// Spelling variants that romanised Hindi/Punjabi is commonly typed with,
+// applied in order before doubled letters are collapsed
+var romanisationVariants = strings.NewReplacer(
+	"ph", "f",
+	"ck", "k",
+	"ee", "i",
+	"oo", "u",
+	"w", "v",
+	"z", "j",
+	"q", "k",
+)
+
+// NormalizeSearchText brings any script and spelling of a title to one
+// comparable form: transliterated, lowercased, punctuation stripped and
+// romanisation variants folded, so "Tere Binaa" and "तेरे बिना" both become "tere bina"
+func NormalizeSearchText(text string) string {
+	text = strings.ToLower(Transliterate(text))
+
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for i, word := range words {
+		words[i] = normalizeRomanWord(word)
+	}
+	return strings.Join(words, " ")
+}
+
+func normalizeRomanWord(word string) string {
+	word = romanisationVariants.Replace(word)
+
+	// Collapse doubled letters: "pyaar" → "pyar", "dill" → "dil"
+	var b strings.Builder
+	var prev rune
+	for _, r := range word {
+		if r != prev || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+		prev = r
+	}
+	word = b.String()
+
+	// A trailing "y" after a consonant is sounded "i": "tery" → "teri"
+	if n := len(word); n > 1 && word[n-1] == 'y' && !strings.ContainsRune("aeiouy", rune(word[n-2])) {
+		word = word[:n-1] + "i"
+	}
+	return word
+}
@@ -0,0 +1,47 @@
+package helpers
+
+import "testing"
+
+func TestTransliterate(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"तेरे बिना", "tere binaa"},
+		{"दिल", "dil"},   // final schwa dropped
+		{"कमल", "kamal"}, // but kept inside the word
+		{"प्यार", "pyaar"},
+		{"नमस्ते", "namaste"},
+		{"ਸਤ ਸ੍ਰੀ ਅਕਾਲ", "sat sree akaal"},
+		{"ਦਿਲ", "dil"},
+		{"Tere Binaa", "Tere Binaa"}, // Latin passes through
+	}
+
+	for _, tt := range tests {
+		if got := Transliterate(tt.text); got != tt.want {
+			t.Errorf("Transliterate(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeSearchText(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"तेरे बिना", "tere bina"},
+		{"Tere Binaa", "tere bina"},
+		{"प्यार", "pyar"},
+		{"Pyaar", "pyar"},
+		{"Dill Se!", "dil se"},
+		{"ਸਤ ਸ੍ਰੀ ਅਕਾਲ", "sat sri akal"},
+		{"Tery", "teri"},
+		{"Phir Milenge", "fir milenge"},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeSearchText(tt.text); got != tt.want {
+			t.Errorf("NormalizeSearchText(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package listenalong
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/ishanbagra18/ecommerce-using-go/models"
+)
+
+const (
+	writeWait    = 10 * time.Second
+	pongWait     = 60 * time.Second
+	pingInterval = pongWait * 9 / 10
+	sendBuffer   = 32
+	maxMessage   = 4096
+)
+
+// Client is one WebSocket connection to a session
+type Client struct {
+	UserID  string
+	Country string // where the connection comes from, "" when unknown
+	conn    *websocket.Conn
+	mu      sync.Mutex
+	send    chan models.ListenEvent
+	closed  bool
+}
+
+// Send queues an event for this client. A client too slow to keep up is
+// disconnected rather than holding the others back.
+func (c *Client) Send(event models.ListenEvent) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return
+	}
+	select {
+	case c.send <- event:
+	default:
+		c.closed = true
+		close(c.send)
+	}
+}
+
+// close ends the connection once everything queued has been written
+func (c *Client) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.closed {
+		c.closed = true
+		close(c.send)
+	}
+}
+
+// Hub tracks the connections of each session on this server. A shared Store
+// would also need events relayed between servers; one server needs none of
+// that.
+type Hub struct {
+	mu      sync.Mutex
+	clients map[string]map[*Client]bool // session_id -> connections
+}
+
+func NewHub() *Hub {
+	return &Hub{clients: make(map[string]map[*Client]bool)}
+}
+
+func (h *Hub) register(sessionID string, client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.clients[sessionID] == nil {
+		h.clients[sessionID] = make(map[*Client]bool)
+	}
+	h.clients[sessionID][client] = true
+}
+
+func (h *Hub) unregister(sessionID string, client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.clients[sessionID], client)
+	if len(h.clients[sessionID]) == 0 {
+		delete(h.clients, sessionID)
+	}
+}
+
+// Broadcast sends an event to every connection in the session
+func (h *Hub) Broadcast(sessionID string, event models.ListenEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for client := range h.clients[sessionID] {
+		client.Send(event)
+	}
+}
+
+// Countries lists the distinct countries the session's connections come from
+func (h *Hub) Countries(sessionID string) []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	seen := make(map[string]bool)
+	var countries []string
+	for client := range h.clients[sessionID] {
+		if !seen[client.Country] {
+			seen[client.Country] = true
+			countries = append(countries, client.Country)
+		}
+	}
+	return countries
+}
+
+// Disconnect sends a last event to everyone in the session and closes their
+// connections, or only those of one user when userID is set
+func (h *Hub) Disconnect(sessionID string, userID string, event models.ListenEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for client := range h.clients[sessionID] {
+		if userID == "" || client.UserID == userID {
+			client.Send(event)
+			client.close()
+		}
+	}
+}
+
+// Serve runs a connection until either side closes it. Every message the
+// client sends is decoded and passed to handle; welcome is sent first.
+func (h *Hub) Serve(sessionID string, userID string, country string, conn *websocket.Conn, welcome models.ListenEvent, handle func(*Client, models.ListenEvent)) {
+	client := &Client{UserID: userID, Country: country, conn: conn, send: make(chan models.ListenEvent, sendBuffer)}
+	h.register(sessionID, client)
+	defer h.unregister(sessionID, client)
+
+	client.Send(welcome)
+	go client.writeLoop()
+
+	conn.SetReadLimit(maxMessage)
+	conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			break
+		}
+		var event models.ListenEvent
+		if err := json.Unmarshal(message, &event); err != nil {
+			client.Send(models.ListenEvent{Type: models.ListenEventError, Error: "invalid message", ServerTime: time.Now().UnixMilli()})
+			continue
+		}
+		handle(client, event)
+	}
+	client.close()
+}
+
+// writeLoop is the only writer on the connection: queued events and pings
+func (c *Client) writeLoop() {
+	ticker := time.NewTicker(pingInterval)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case event, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := c.conn.WriteJSON(event); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
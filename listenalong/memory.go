@@ -0,0 +1,107 @@
+package listenalong
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ishanbagra18/ecommerce-using-go/models"
+)
+
+// MemoryStore keeps sessions in this process; they are lost on restart
+type MemoryStore struct {
+	mu       sync.Mutex
+	sessions map[string]models.ListenSession
+	codes    map[string]string // invite code -> session_id
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		sessions: make(map[string]models.ListenSession),
+		codes:    make(map[string]string),
+	}
+}
+
+// clone copies the participants so callers can't change a stored session
+func clone(session models.ListenSession) models.ListenSession {
+	session.Participants = append([]string{}, session.Participants...)
+	return session
+}
+
+func (s *MemoryStore) Create(ctx context.Context, session models.ListenSession) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.codes[session.InviteCode]; ok {
+		return ErrCodeTaken
+	}
+	s.sessions[session.SessionID] = clone(session)
+	s.codes[session.InviteCode] = session.SessionID
+	return nil
+}
+
+func (s *MemoryStore) Get(ctx context.Context, sessionID string) (models.ListenSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[sessionID]
+	if !ok {
+		return models.ListenSession{}, ErrNotFound
+	}
+	return clone(session), nil
+}
+
+func (s *MemoryStore) FindByCode(ctx context.Context, code string) (models.ListenSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[s.codes[code]]
+	if !ok {
+		return models.ListenSession{}, ErrNotFound
+	}
+	return clone(session), nil
+}
+
+func (s *MemoryStore) Update(ctx context.Context, sessionID string, change func(*models.ListenSession) error) (models.ListenSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.sessions[sessionID]
+	if !ok {
+		return models.ListenSession{}, ErrNotFound
+	}
+	session := clone(stored)
+	if err := change(&session); err != nil {
+		return clone(stored), err
+	}
+	s.sessions[sessionID] = clone(session)
+	return session, nil
+}
+
+func (s *MemoryStore) Delete(ctx context.Context, sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[sessionID]
+	if !ok {
+		return ErrNotFound
+	}
+	delete(s.codes, session.InviteCode)
+	delete(s.sessions, sessionID)
+	return nil
+}
+
+func (s *MemoryStore) Expire(ctx context.Context, cutoff time.Time) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var expired []string
+	for sessionID, session := range s.sessions {
+		if session.ActiveAt.Before(cutoff) {
+			delete(s.codes, session.InviteCode)
+			delete(s.sessions, sessionID)
+			expired = append(expired, sessionID)
+		}
+	}
+	return expired, nil
+}
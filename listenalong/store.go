@@ -0,0 +1,31 @@
+// Package listenalong keeps group listening sessions and fans their playback
+// events out to the WebSocket connections of everyone listening.
+package listenalong
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ishanbagra18/ecommerce-using-go/models"
+)
+
+var (
+	ErrNotFound  = errors.New("listen-along session not found")
+	ErrCodeTaken = errors.New("invite code already in use")
+)
+
+// Store keeps listen-along sessions. MemoryStore is enough while the API runs
+// on one server; a shared store can implement the same interface once it runs
+// on several.
+type Store interface {
+	Create(ctx context.Context, session models.ListenSession) error
+	Get(ctx context.Context, sessionID string) (models.ListenSession, error)
+	FindByCode(ctx context.Context, code string) (models.ListenSession, error)
+	// Update applies change atomically and returns the session as saved.
+	// If change returns an error nothing is saved.
+	Update(ctx context.Context, sessionID string, change func(*models.ListenSession) error) (models.ListenSession, error)
+	Delete(ctx context.Context, sessionID string) error
+	// Expire removes sessions idle since before the cutoff and returns their IDs
+	Expire(ctx context.Context, cutoff time.Time) ([]string, error)
+}
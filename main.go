@@ -11,6 +11,7 @@ import (
 	"github.com/ishanbagra18/ecommerce-using-go/controllers"
 	"github.com/ishanbagra18/ecommerce-using-go/database"
 	"github.com/ishanbagra18/ecommerce-using-go/helpers"
+	"github.com/ishanbagra18/ecommerce-using-go/middleware"
 	"github.com/ishanbagra18/ecommerce-using-go/routes"
 	"github.com/joho/godotenv"
 )
@@ -25,11 +26,34 @@ func main() {
 	log.Println("✅ [main] MongoDB initialized successfully")
 
 	helpers.InitUserController()
+	if err := helpers.InitGeoIP(); err != nil {
+		log.Println("⚠️  [main] GeoIP database not loaded, countries come from COUNTRY_HEADER only:", err)
+	}
 	controllers.InitUserController()
 	controllers.InitMusicController()
 	controllers.InitPlaylistController()
 	controllers.InitHistoryController()
 	controllers.InitArtistController()
+	controllers.InitNotificationController()
+	controllers.InitTrendingController()
+	controllers.InitAlbumController()
+	controllers.InitLyricsController()
+	controllers.InitGenreController()
+	controllers.InitRecommendationController()
+	controllers.InitRelatedController()
+	controllers.InitRadioController()
+	controllers.InitPlaybackController()
+	controllers.InitCommentController()
+	controllers.RunMigrations()
+
+	controllers.StartAnalysisWorker()
+	controllers.StartReleaseScheduler()
+	controllers.StartSearchIndexer()
+	controllers.StartTrendingJob()
+	controllers.StartRecommendationJob()
+	controllers.StartRelatedJob()
+	controllers.StartMixJob()
+	controllers.StartListenAlongExpiry()
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -59,6 +83,24 @@ func main() {
 	router := gin.New()
 	router.Use(gin.Logger())
 
+	// Only these proxies may set X-Forwarded-For and the COUNTRY_HEADER;
+	// without any, both come from the connecting address alone
+	var trustedProxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if trimmed := strings.TrimSpace(proxy); trimmed != "" {
+			trustedProxies = append(trustedProxies, trimmed)
+		}
+	}
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		log.Fatalf("❌ [main] Invalid TRUSTED_PROXIES: %v\n", err)
+	}
+	if err := helpers.SetTrustedProxies(trustedProxies); err != nil {
+		log.Fatalf("❌ [main] Invalid TRUSTED_PROXIES: %v\n", err)
+	}
+	if len(trustedProxies) > 0 {
+		log.Printf("🔍 [main] Trusted proxies configured: %v\n", trustedProxies)
+	}
+
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     corsOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
@@ -68,6 +110,9 @@ func main() {
 		MaxAge:           12 * time.Hour,
 	}))
 
+	// Songs licensed for some countries only are filtered by where requests come from
+	router.Use(middleware.Country())
+
 	log.Println("✅ [main] Gin router initialized")
 
 	log.Println("🔍 [main] Registering routes...")
@@ -79,6 +124,18 @@ func main() {
 	routes.StatsRoutes(router)
 	routes.ArtistRoutes(router)
 	routes.MessageRoutes(router)
+	routes.AdminRoutes(router)
+	routes.NotificationRoutes(router)
+	routes.SearchRoutes(router)
+	routes.PlayRoutes(router)
+	routes.AlbumRoutes(router)
+	routes.GenreRoutes(router)
+	routes.RecommendationRoutes(router)
+	routes.RadioRoutes(router)
+	routes.PlaybackRoutes(router)
+	routes.QueueRoutes(router)
+	routes.ListenAlongRoutes(router)
+	routes.CommentRoutes(router)
 	log.Println("✅ [main] Routes registered")
 
 	router.GET("/api-1", func(c *gin.Context) {
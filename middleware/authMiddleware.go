@@ -34,6 +34,7 @@ func Authentication() gin.HandlerFunc {
         }
 
         c.Set("user_id", claims.Uid)
+        c.Set("user_type", claims.User_type)
         c.Next()
     }
 }
@@ -53,8 +54,43 @@ func OptionalAuthentication() gin.HandlerFunc {
             claims, err := helper.ValidateToken(token)
             if err == nil {
                 c.Set("user_id", claims.Uid)
+                c.Set("user_type", claims.User_type)
             }
         }
         c.Next()
     }
-}
\ No newline at end of file
+}
+
+// Use this after Authentication() for routes that only admins may call
+func AdminOnly() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        if err := helper.CheckUserType(c, "ADMIN"); err != nil {
+            c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+            c.Abort()
+            return
+        }
+        c.Next()
+    }
+}
+
+// Use this for WebSocket routes: browsers can't set headers on the handshake,
+// so a single-use ?ticket= from helper.IssueSocketTicket stands in for the token
+func SocketAuthentication() gin.HandlerFunc {
+    authenticate := Authentication()
+    return func(c *gin.Context) {
+        if c.GetHeader("Authorization") != "" {
+            authenticate(c)
+            return
+        }
+
+        userID, userType, ok := helper.RedeemSocketTicket(c.Query("ticket"))
+        if !ok {
+            c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired ticket"})
+            c.Abort()
+            return
+        }
+        c.Set("user_id", userID)
+        c.Set("user_type", userType)
+        c.Next()
+    }
+}
@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	helper "github.com/ishanbagra18/ecommerce-using-go/helpers"
+)
+
+// Use this on the whole router: it sets "country" to the request's country
+// code, or leaves it unset when it can't be resolved
+func Country() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if code := helper.RequestCountry(c); code != "" {
+			c.Set("country", code)
+		}
+		c.Next()
+	}
+}
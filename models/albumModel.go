@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type AlbumType string
+
+const (
+	AlbumTypeAlbum  AlbumType = "album"
+	AlbumTypeSingle AlbumType = "single"
+	AlbumTypeEP     AlbumType = "ep"
+)
+
+// AlbumTrack places a song on an album; tracks are kept sorted by disc, then track number
+type AlbumTrack struct {
+	SongID      string `bson:"song_id" json:"song_id" binding:"required"`
+	DiscNumber  int    `bson:"disc_number" json:"disc_number"`
+	TrackNumber int    `bson:"track_number" json:"track_number"`
+}
+
+type Album struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	AlbumID     string             `bson:"album_id" json:"album_id"`
+	Title       *string            `bson:"title" json:"title" validate:"required,min=1,max=200"`
+	Artist      *string            `bson:"artist,omitempty" json:"artist,omitempty"` // display credit, e.g. "Arijit Singh, Shreya Ghoshal"
+	ArtistIDs   []string           `bson:"artist_ids,omitempty" json:"artist_ids,omitempty"`
+	Type        AlbumType          `bson:"type" json:"type"`
+	ReleaseDate *time.Time         `bson:"release_date,omitempty" json:"release_date,omitempty"`
+	CoverURL    *string            `bson:"cover_url,omitempty" json:"cover_url,omitempty"`
+	Tracks      []AlbumTrack       `bson:"tracks" json:"tracks"`
+	CreatedBy   string             `bson:"created_by" json:"created_by"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+func IsValidAlbumType(t AlbumType) bool {
+	return t == AlbumTypeAlbum || t == AlbumTypeSingle || t == AlbumTypeEP
+}
@@ -27,3 +27,24 @@ type SocialLinks struct {
 	Facebook  *string `bson:"facebook,omitempty" json:"facebook,omitempty"`
 	Website   *string `bson:"website,omitempty" json:"website,omitempty"`
 }
+
+type ArtistRole string
+
+const (
+	ArtistRolePrimary  ArtistRole = "primary"
+	ArtistRoleFeatured ArtistRole = "featured"
+	ArtistRoleProducer ArtistRole = "producer"
+	ArtistRoleComposer ArtistRole = "composer"
+)
+
+func IsValidArtistRole(role ArtistRole) bool {
+	return role == ArtistRolePrimary || role == ArtistRoleFeatured || role == ArtistRoleProducer || role == ArtistRoleComposer
+}
+
+// ArtistCredit links a song to an artist. Name is copied from the artist
+// so song lists can show credits without a lookup.
+type ArtistCredit struct {
+	ArtistID string     `bson:"artist_id" json:"artist_id"`
+	Name     string     `bson:"name" json:"name"`
+	Role     ArtistRole `bson:"role" json:"role"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CommentStatus string
+
+const (
+	CommentVisible CommentStatus = "visible"
+	CommentPending CommentStatus = "pending" // held by a moderation check or by reports until an admin looks
+	CommentRemoved CommentStatus = "removed" // taken down by an admin
+)
+
+// Comment is a comment on a song, optionally pinned to a moment in it.
+// Replies point at a top-level comment; threads are one level deep.
+type Comment struct {
+	ID               primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	CommentID        string             `bson:"comment_id" json:"comment_id"`
+	SongID           string             `bson:"song_id" json:"song_id"`
+	UserID           string             `bson:"user_id" json:"user_id"`
+	ParentID         *string            `bson:"parent_id,omitempty" json:"parent_id,omitempty"`
+	Body             string             `bson:"body" json:"body"`
+	Timestamp        *int               `bson:"timestamp,omitempty" json:"timestamp,omitempty"` // seconds into the song
+	Status           CommentStatus      `bson:"status" json:"status"`
+	ModerationReason *string            `bson:"moderation_reason,omitempty" json:"moderation_reason,omitempty"`
+	ReportedBy       []string           `bson:"reported_by,omitempty" json:"-"`
+	ReplyCount       int                `bson:"reply_count" json:"reply_count"`             // visible replies
+	Deleted          bool               `bson:"deleted,omitempty" json:"deleted,omitempty"` // deleted by its author but kept for its replies
+	EditedAt         *time.Time         `bson:"edited_at,omitempty" json:"edited_at,omitempty"`
+	CreatedAt        time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt        time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// Counted reports whether the comment counts towards its song's comment_count
+// and its parent's reply_count
+func (c Comment) Counted() bool {
+	return c.Status == CommentVisible && !c.Deleted
+}
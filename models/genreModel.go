@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type GenreKind string
+
+const (
+	GenreKindGenre GenreKind = "genre"
+	GenreKindMood  GenreKind = "mood"
+)
+
+// Genre is one entry of the managed taxonomy: a genre such as "punjabi-pop"
+// under "pop", or a mood such as "workout". Songs store the slug, and the
+// name is what clients show.
+type Genre struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	Slug      string             `bson:"slug" json:"slug"`
+	Name      string             `bson:"name" json:"name"`
+	Kind      GenreKind          `bson:"kind" json:"kind"`
+	Parent    *string            `bson:"parent,omitempty" json:"parent,omitempty"`   // slug of the parent genre
+	Aliases   []string           `bson:"aliases,omitempty" json:"aliases,omitempty"` // other slugs that mean this one
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// GenreRequest is a genre uploaders used that the taxonomy doesn't have yet.
+// Their songs keep the name as typed, unlinked, until an admin adds the genre
+// (or an alias that covers it) or dismisses the request.
+type GenreRequest struct {
+	Slug      string    `bson:"slug" json:"slug"`
+	Names     []string  `bson:"names" json:"names"` // as uploaders typed it
+	Uses      int       `bson:"uses" json:"uses"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+func IsValidGenreKind(kind GenreKind) bool {
+	return kind == GenreKindGenre || kind == GenreKindMood
+}
@@ -8,11 +8,21 @@ import (
 
 // models/history.go
 
+// History is one play session, opened by POST /plays/start and updated with
+// progress until the user stops listening
 type History struct {
-    ID       primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-    UserID   string             `bson:"user_id" json:"user_id"`
-    SongID   string             `bson:"song_id" json:"song_id"`
-    PlayedAt time.Time          `bson:"played_at" json:"played_at"`
-    Duration int `bson:"duration,omitempty" json:"duration,omitempty"` // seconds
-
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID     string             `bson:"user_id" json:"user_id"`
+	SongID     string             `bson:"song_id" json:"song_id"`
+	PlayedAt   time.Time          `bson:"played_at" json:"played_at"`                   // when playback started
+	Duration   int                `bson:"duration,omitempty" json:"duration,omitempty"` // seconds actually listened
+	Completion float64            `bson:"completion" json:"completion"`                 // percent of the song listened, 0-100
+	Source     string             `bson:"source,omitempty" json:"source,omitempty"`     // playlist | search | artist | album | radio | library | other
+	SourceID   *string            `bson:"source_id,omitempty" json:"source_id,omitempty"`
+	Device     string             `bson:"device,omitempty" json:"device,omitempty"`
+	Counted    bool               `bson:"counted" json:"counted"` // true once the play passed the threshold and was added to play_count
+	Ended      bool               `bson:"ended" json:"ended"`
+	UpdatedAt  time.Time          `bson:"updated_at" json:"updated_at"`
 }
+
+var PlaySources = []string{"playlist", "search", "artist", "album", "radio", "library", "other"}
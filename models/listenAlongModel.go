@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// ListenSession is a group listening together: the host controls playback and
+// everyone who joined with the invite code follows along
+type ListenSession struct {
+	SessionID    string    `bson:"session_id" json:"session_id"`
+	InviteCode   string    `bson:"invite_code" json:"invite_code"`
+	HostID       string    `bson:"host_id" json:"host_id"`
+	Participants []string  `bson:"participants" json:"participants"` // user_ids, host included
+	SongID       *string   `bson:"song_id" json:"song_id"`
+	Position     float64   `bson:"position" json:"position"` // seconds into SongID at UpdatedAt
+	IsPlaying    bool      `bson:"is_playing" json:"is_playing"`
+	UpdatedAt    time.Time `bson:"updated_at" json:"updated_at"` // server time Position was taken at
+	ActiveAt     time.Time `bson:"active_at" json:"-"`           // last control, join or leave, for expiry
+	CreatedAt    time.Time `bson:"created_at" json:"created_at"`
+}
+
+type ListenEventType string
+
+const (
+	ListenEventState  ListenEventType = "state" // full state, sent on connect
+	ListenEventPlay   ListenEventType = "play"
+	ListenEventPause  ListenEventType = "pause"
+	ListenEventSeek   ListenEventType = "seek"
+	ListenEventTrack  ListenEventType = "track"
+	ListenEventJoined ListenEventType = "joined"
+	ListenEventLeft   ListenEventType = "left"
+	ListenEventEnded  ListenEventType = "ended"
+	ListenEventPing   ListenEventType = "ping"
+	ListenEventPong   ListenEventType = "pong"
+	ListenEventError  ListenEventType = "error"
+)
+
+// ListenEvent is what goes over the WebSocket. ServerTime is when the server
+// applied the event, in Unix milliseconds; a client playing along seeks to
+// Position plus the time since ServerTime, corrected by its clock offset from
+// a ping.
+type ListenEvent struct {
+	Type       ListenEventType `json:"type"`
+	UserID     string          `json:"user_id,omitempty"` // who caused it
+	SongID     *string         `json:"song_id,omitempty"`
+	Position   float64         `json:"position"`
+	IsPlaying  bool            `json:"is_playing"`
+	ServerTime int64           `json:"server_time"`
+	ClientTime int64           `json:"client_time,omitempty"` // echoed back on pong
+	Session    *ListenSession  `json:"session,omitempty"`     // on state
+	Error      string          `json:"error,omitempty"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Lyrics are stored apart from the song so song lists stay small.
+// Synced lyrics keep both the timed lines and the plain text built from them.
+type Lyrics struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	SongID    string             `bson:"song_id" json:"song_id"`
+	Plain     string             `bson:"plain" json:"plain"`
+	Lines     []LyricLine        `bson:"lines,omitempty" json:"lines,omitempty"`
+	Synced    bool               `bson:"synced" json:"synced"`
+	Language  *string            `bson:"language,omitempty" json:"language,omitempty"`
+	UpdatedBy string             `bson:"updated_by" json:"updated_by"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+type LyricLine struct {
+	TimeMs int64  `bson:"time_ms" json:"time_ms"`
+	Text   string `bson:"text" json:"text"`
+}
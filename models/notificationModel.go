@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type NotificationType string
+
+const (
+	NotificationNewRelease NotificationType = "new_release"
+)
+
+type Notification struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	NotificationID string             `bson:"notification_id" json:"notification_id"`
+	UserID         string             `bson:"user_id" json:"user_id"`
+	Type           NotificationType   `bson:"type" json:"type"`
+	Message        string             `bson:"message" json:"message"`
+	SongID         *string            `bson:"song_id,omitempty" json:"song_id,omitempty"`
+	ArtistID       *string            `bson:"artist_id,omitempty" json:"artist_id,omitempty"`
+	Read           bool               `bson:"read" json:"read"`
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+}
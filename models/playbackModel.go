@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+type RepeatMode string
+
+const (
+	RepeatOff RepeatMode = "off"
+	RepeatAll RepeatMode = "all"
+	RepeatOne RepeatMode = "one"
+)
+
+func IsValidRepeatMode(mode RepeatMode) bool {
+	return mode == RepeatOff || mode == RepeatAll || mode == RepeatOne
+}
+
+type QueueSourceType string
+
+const (
+	QueueSourcePlaylist QueueSourceType = "playlist"
+	QueueSourceAlbum    QueueSourceType = "album"
+	QueueSourceArtist   QueueSourceType = "artist"
+)
+
+func IsValidQueueSourceType(sourceType QueueSourceType) bool {
+	return sourceType == QueueSourcePlaylist || sourceType == QueueSourceAlbum || sourceType == QueueSourceArtist
+}
+
+// QueueSource is the playlist, album or artist a queue was started from
+type QueueSource struct {
+	Type QueueSourceType `bson:"type" json:"type"`
+	ID   string          `bson:"id" json:"id"`
+	Name string          `bson:"name" json:"name"`
+}
+
+// PlaybackState is where a user is in their listening and what comes next,
+// shared by all their devices so they can pick up on one where they stopped
+// on another. Version goes up on every change; a write must name the version
+// it was based on.
+//
+// With Shuffle on, Queue starts as SourceSongs put through a Fisher-Yates
+// shuffle seeded with ShuffleSeed (Go's math/rand), minus the songs already
+// played, so every device can rebuild the order. Songs moved or removed by hand afterwards
+// keep their new place.
+type PlaybackState struct {
+	UserID      string       `bson:"user_id" json:"user_id"`
+	SongID      *string      `bson:"song_id" json:"song_id"`
+	Position    float64      `bson:"position" json:"position"` // seconds into SongID
+	IsPlaying   bool         `bson:"is_playing" json:"is_playing"`
+	UpNext      []string     `bson:"up_next" json:"up_next"` // songs queued by hand; they play before Queue
+	Queue       []string     `bson:"queue" json:"queue"`     // the rest of Source, in play order
+	History     []string     `bson:"history" json:"history"` // songs played before SongID, most recent last
+	Source      *QueueSource `bson:"source,omitempty" json:"source,omitempty"`
+	SourceSongs []string     `bson:"source_songs" json:"source_songs"` // Source's songs in their own order, to unshuffle Queue
+	Shuffle     bool         `bson:"shuffle" json:"shuffle"`
+	ShuffleSeed int64        `bson:"shuffle_seed" json:"shuffle_seed"`
+	Repeat      RepeatMode   `bson:"repeat" json:"repeat"`
+	DeviceID    string       `bson:"device_id" json:"device_id"` // the device playing right now
+	DeviceName  string       `bson:"device_name,omitempty" json:"device_name,omitempty"`
+	Version     int64        `bson:"version" json:"version"`
+	UpdatedAt   time.Time    `bson:"updated_at" json:"updated_at"`
+}
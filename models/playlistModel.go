@@ -24,7 +24,11 @@ type Playlist struct {
 	SongIDs     []string           `bson:"song_ids,omitempty" json:"song_ids,omitempty"`
 	IsPublic    bool               `bson:"is_public" json:"is_public"`
 	IsSeeded    bool               `bson:"is_seeded,omitempty" json:"is_seeded,omitempty"`
+	MixKey      *string            `bson:"mix_key,omitempty" json:"mix_key,omitempty"`   // set on generated mixes: daily_mix_1, discover_weekly, ...
+	OwnerID     *string            `bson:"owner_id,omitempty" json:"owner_id,omitempty"` // the listener a generated mix was made for
 	PlayCount   int64              `bson:"play_count,omitempty" json:"play_count,omitempty"`
 	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
 	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+
+	UnavailableSongIDs []string `bson:"-" json:"unavailable_song_ids,omitempty"` // listed but not licensed where the request came from
 }
\ No newline at end of file
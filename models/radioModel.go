@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type RadioSeedType string
+
+const (
+	RadioSeedSong     RadioSeedType = "song"
+	RadioSeedArtist   RadioSeedType = "artist"
+	RadioSeedGenre    RadioSeedType = "genre"
+	RadioSeedPlaylist RadioSeedType = "playlist"
+)
+
+func IsValidRadioSeedType(seedType RadioSeedType) bool {
+	return seedType == RadioSeedSong || seedType == RadioSeedArtist || seedType == RadioSeedGenre || seedType == RadioSeedPlaylist
+}
+
+// RadioStation is one user's endless station. It remembers what it has
+// played and the user's thumbs so each batch steers away from repeats and
+// towards what they liked. Idle stations expire after 30 days.
+type RadioStation struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	StationID      string             `bson:"station_id" json:"station_id"`
+	UserID         string             `bson:"user_id" json:"user_id"`
+	SeedType       RadioSeedType      `bson:"seed_type" json:"seed_type"`
+	SeedID         string             `bson:"seed_id" json:"seed_id"` // song_id, artist_id, genre slug or playlist id
+	Name           string             `bson:"name" json:"name"`
+	Played         []string           `bson:"played" json:"-"`                        // song_ids handed out, most recent last
+	RecentArtists  [][]string         `bson:"recent_artists" json:"-"`                // artists of the last few tracks, for spacing
+	Liked          []string           `bson:"liked" json:"liked"`                     // thumbs up, used as extra seeds
+	Disliked       []string           `bson:"disliked" json:"disliked"`               // thumbs down, never played again
+	ArtistFeedback map[string]int     `bson:"artist_feedback" json:"artist_feedback"` // artist_id -> ups minus downs
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt      time.Time          `bson:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ReactionType string
+
+const (
+	ReactionLike ReactionType = "like"
+	ReactionSave ReactionType = "save"
+)
+
+// SongReaction records when a user liked or saved a song, for time-windowed rankings.
+// It is removed again when the like or save is undone.
+type SongReaction struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	SongID    string             `bson:"song_id" json:"song_id"`
+	UserID    string             `bson:"user_id" json:"user_id"`
+	Type      ReactionType       `bson:"type" json:"type"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
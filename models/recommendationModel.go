@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// SongSimilarity lists the songs most often played by the same listeners as
+// SongID, most similar first. It is rebuilt offline from play history.
+type SongSimilarity struct {
+	SongID     string        `bson:"song_id" json:"song_id"`
+	Similar    []SimilarSong `bson:"similar" json:"similar"`
+	ComputedAt time.Time     `bson:"computed_at" json:"computed_at"`
+}
+
+type SimilarSong struct {
+	SongID string  `bson:"song_id" json:"song_id"`
+	Score  float64 `bson:"score" json:"score"` // cosine similarity, 0-1
+}
+
+// RelatedArtists lists the artists most related to ArtistID, best first,
+// from shared followers and from being played in the same sessions
+type RelatedArtists struct {
+	ArtistID   string          `bson:"artist_id" json:"artist_id"`
+	Related    []RelatedArtist `bson:"related" json:"related"`
+	ComputedAt time.Time       `bson:"computed_at" json:"computed_at"`
+}
+
+type RelatedArtist struct {
+	ArtistID string  `bson:"artist_id" json:"artist_id"`
+	Score    float64 `bson:"score" json:"score"`
+}
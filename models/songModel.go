@@ -11,20 +11,124 @@ type Song struct {
 	Title       *string            `bson:"title" json:"title" validate:"required,min=2,max=100"`
 	Artist      *string            `bson:"artist" json:"artist" validate:"required,min=2,max=100"`
 	Artists     []string           `bson:"artists,omitempty" json:"artists,omitempty"`
-	Album       *string            `bson:"album" json:"album"`
+	Album       *string            `bson:"album" json:"album"` // album title, kept in step with AlbumID
+	AlbumID     *string            `bson:"album_id,omitempty" json:"album_id,omitempty"`
 	Info        *string            `bson:"info" json:"info"`
-	Genre       *string            `bson:"genre" json:"genre"`
+	Genre       *string            `bson:"genre" json:"genre"` // display name of GenreSlug
 	Language    *string            `bson:"language" json:"language"`
 	FileURL     *string            `bson:"file_url" json:"file_url" validate:"required"`
 	ImageURL    *string            `bson:"image_url,omitempty" json:"image_url,omitempty"`
 	UploadedBy  *string            `bson:"uploaded_by" json:"uploaded_by" validate:"required"`
 	Likes       []string           `bson:"likes,omitempty" json:"likes,omitempty"`
 	Saves       []string           `bson:"saves,omitempty" json:"saves,omitempty"`
+	LikeCount   int                `bson:"like_count" json:"like_count"` // len(likes), kept in step by ToggleLikeSong
+	SaveCount   int                `bson:"save_count" json:"save_count"` // len(saves), kept in step by ToggleSave
 	CreatedAt   *time.Time         `bson:"created_at" json:"created_at"`
 	UpdatedAt   *time.Time         `bson:"updated_at" json:"updated_at"`
 	SongID      string             `bson:"song_id" json:"song_id"`
 	ReleaseDate *time.Time         `bson:"release_date,omitempty" json:"release_date,omitempty"`
 
+	ArtistIDs     []string       `bson:"artist_ids,omitempty" json:"artist_ids,omitempty"` // every credited artist, for lookups
+	ArtistCredits []ArtistCredit `bson:"artist_credits,omitempty" json:"artist_credits,omitempty"`
+
+	Credits   *SongCredits `bson:"credits,omitempty" json:"credits,omitempty"`
+	HasLyrics bool         `bson:"has_lyrics,omitempty" json:"has_lyrics,omitempty"`
+	HasSynced bool         `bson:"has_synced_lyrics,omitempty" json:"has_synced_lyrics,omitempty"` // lyrics carry line timings
+
+	GenreSlug *string  `bson:"genre_slug,omitempty" json:"genre_slug,omitempty"` // taxonomy entry behind Genre
+	Moods     []string `bson:"moods,omitempty" json:"moods,omitempty"`           // mood slugs
+
+	Explicit bool `bson:"explicit,omitempty" json:"explicit"` // hidden from listeners who turned on hide_explicit
+
+	Availability *SongAvailability `bson:"availability,omitempty" json:"availability,omitempty"` // licensing: where and when it may be played
+
+	CommentCount int `bson:"comment_count" json:"comment_count"` // visible comments, replies included
+
 	PlayCount      int            `bson:"play_count" json:"play_count"`                                 // Total play count
 	UserPlayCounts map[string]int `bson:"user_play_counts,omitempty" json:"user_play_counts,omitempty"` // user_id -> play count
+
+	Duration    int      `bson:"duration,omitempty" json:"duration,omitempty"`         // seconds
+	ContentHash string   `bson:"content_hash,omitempty" json:"content_hash,omitempty"` // SHA-256 of the uploaded file
+	Fingerprint string   `bson:"fingerprint,omitempty" json:"fingerprint,omitempty"`   // normalized "title|artists"
+	DuplicateOf *string  `bson:"duplicate_of,omitempty" json:"duplicate_of,omitempty"` // song_id this one probably duplicates
+	NeedsReview bool     `bson:"needs_review,omitempty" json:"needs_review,omitempty"` // flagged as a near-duplicate
+	MergedInto  *string  `bson:"merged_into,omitempty" json:"-"`                       // set on a duplicate while it is merged away
+	MergedFrom  []string `bson:"merged_from,omitempty" json:"-"`                       // duplicates whose counters were added to this song
+
+	Waveform          []float64  `bson:"waveform,omitempty" json:"-"`                                // downsampled peaks, served by /song/:song_id/waveform
+	LoudnessLUFS      *float64   `bson:"loudness_lufs,omitempty" json:"loudness_lufs,omitempty"`     // integrated loudness
+	AnalysisStatus    string     `bson:"analysis_status,omitempty" json:"analysis_status,omitempty"` // pending | processing | done | failed
+	AnalysisError     *string    `bson:"analysis_error,omitempty" json:"analysis_error,omitempty"`
+	AnalyzedAt        *time.Time `bson:"analyzed_at,omitempty" json:"analyzed_at,omitempty"`
+	AnalysisStartedAt *time.Time `bson:"processing_started_at,omitempty" json:"-"` // when the current attempt claimed the song
+	AnalysisAttempts  int        `bson:"analysis_attempts,omitempty" json:"-"`     // failed attempts since the last success
+
+	Status           string     `bson:"status" json:"status"` // pending | approved | rejected | taken_down
+	ModerationReason *string    `bson:"moderation_reason,omitempty" json:"moderation_reason,omitempty"`
+	ReviewedBy       *string    `bson:"reviewed_by,omitempty" json:"reviewed_by,omitempty"`
+	ReviewedAt       *time.Time `bson:"reviewed_at,omitempty" json:"reviewed_at,omitempty"`
+
+	IsReleased      bool       `bson:"is_released" json:"is_released"`                               // false while release_date is in the future
+	ReleaseNotified *bool      `bson:"release_notified,omitempty" json:"release_notified,omitempty"` // set for scheduled releases; true once followers were told
+	NotifyingAt     *time.Time `bson:"notifying_at,omitempty" json:"-"`                              // when an instance took on telling the followers
 }
+
+// SongCredits are the people and labels behind a song, as written on the
+// release. They are names, not links to artist profiles.
+type SongCredits struct {
+	Writers   []string `bson:"writers,omitempty" json:"writers,omitempty"`
+	Producers []string `bson:"producers,omitempty" json:"producers,omitempty"`
+	Composers []string `bson:"composers,omitempty" json:"composers,omitempty"`
+	Labels    []string `bson:"labels,omitempty" json:"labels,omitempty"`
+}
+
+// SongAvailability is the licensing window of a song. A song with no rules
+// plays everywhere, always.
+type SongAvailability struct {
+	AllowedCountries []string   `bson:"allowed_countries,omitempty" json:"allowed_countries,omitempty"` // ISO codes; when set, only these
+	BlockedCountries []string   `bson:"blocked_countries,omitempty" json:"blocked_countries,omitempty"`
+	StartsAt         *time.Time `bson:"starts_at,omitempty" json:"starts_at,omitempty"`
+	EndsAt           *time.Time `bson:"ends_at,omitempty" json:"ends_at,omitempty"`
+}
+
+// AvailableIn reports whether the song may be played in country at the given
+// time. An unknown country ("") gets past block lists but not allow lists.
+func (a *SongAvailability) AvailableIn(country string, at time.Time) bool {
+	if a == nil {
+		return true
+	}
+	if a.StartsAt != nil && a.StartsAt.After(at) {
+		return false
+	}
+	if a.EndsAt != nil && !a.EndsAt.After(at) {
+		return false
+	}
+	for _, blocked := range a.BlockedCountries {
+		if blocked == country {
+			return false
+		}
+	}
+	if len(a.AllowedCountries) == 0 {
+		return true
+	}
+	for _, allowed := range a.AllowedCountries {
+		if allowed == country {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	SongStatusPending   = "pending"
+	SongStatusApproved  = "approved"
+	SongStatusRejected  = "rejected"
+	SongStatusTakenDown = "taken_down"
+)
+
+const (
+	AnalysisPending    = "pending"
+	AnalysisProcessing = "processing"
+	AnalysisDone       = "done"
+	AnalysisFailed     = "failed"
+)
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// TrendingList is one precomputed trending ranking, best first.
+// Scope is "global", "genre:<genre>" or "language:<language>".
+type TrendingList struct {
+	Scope      string          `bson:"scope" json:"scope"`
+	Songs      []TrendingEntry `bson:"songs" json:"songs"`
+	ComputedAt time.Time       `bson:"computed_at" json:"computed_at"`
+}
+
+type TrendingEntry struct {
+	SongID   string  `bson:"song_id" json:"song_id"`
+	Score    float64 `bson:"score" json:"score"`
+	Plays24h int     `bson:"plays_24h" json:"plays_24h"`
+	Velocity float64 `bson:"velocity" json:"velocity"`
+}
@@ -20,4 +20,6 @@ type User struct {
 	Updated_at      *time.Time         `json:"updated_at"`
 	User_id         string             `json:"user_id"`
 	FollowedArtists []string           `bson:"followed_artists,omitempty" json:"followed_artists,omitempty"`
+	TrustedUploader bool               `bson:"trusted_uploader,omitempty" json:"trusted_uploader,omitempty"` // uploads skip the moderation queue
+	HideExplicit    *bool              `bson:"hide_explicit,omitempty" json:"hide_explicit,omitempty"`       // keep songs marked explicit out of every listing
 }
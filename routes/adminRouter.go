@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	controller "github.com/ishanbagra18/ecommerce-using-go/controllers"
+	"github.com/ishanbagra18/ecommerce-using-go/middleware"
+)
+
+func AdminRoutes(router *gin.Engine) {
+
+	// 🔐 ADMIN ONLY ROUTES
+	adminGroup := router.Group("/admin")
+	adminGroup.Use(middleware.Authentication(), middleware.AdminOnly())
+	{
+		adminGroup.GET("/songs/duplicates", controller.GetDuplicateCandidates())
+		adminGroup.POST("/songs/merge", controller.MergeSongs())
+		adminGroup.POST("/songs/:song_id/dismiss-duplicate", controller.DismissDuplicate())
+
+		adminGroup.GET("/moderation/queue", controller.GetModerationQueue())
+		adminGroup.POST("/moderation/:song_id/approve", controller.ApproveSong())
+		adminGroup.POST("/moderation/:song_id/reject", controller.RejectSong())
+		adminGroup.POST("/moderation/:song_id/takedown", controller.TakeDownSong())
+		adminGroup.PUT("/users/:user_id/trusted", controller.SetTrustedUploader())
+
+		adminGroup.POST("/genres", controller.CreateGenre())
+		adminGroup.PATCH("/genres/:slug", controller.UpdateGenre())
+		adminGroup.POST("/genres/:slug/merge", controller.MergeGenre())
+		adminGroup.GET("/genres/requests", controller.GetGenreRequests())
+		adminGroup.DELETE("/genres/requests/:slug", controller.DismissGenreRequest())
+
+		adminGroup.GET("/comments", controller.GetCommentQueue())
+		adminGroup.POST("/comments/:comment_id/approve", controller.ApproveComment())
+		adminGroup.POST("/comments/:comment_id/remove", controller.RemoveComment())
+	}
+}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	controller "github.com/ishanbagra18/ecommerce-using-go/controllers"
+	"github.com/ishanbagra18/ecommerce-using-go/middleware"
+)
+
+func AlbumRoutes(router *gin.Engine) {
+	// 🌍 Public, optional auth so uploaders see their own unreleased tracks
+	router.GET("/albums", middleware.OptionalAuthentication(), controller.GetAlbums())
+	router.GET("/albums/:album_id", middleware.OptionalAuthentication(), controller.GetAlbumByID())
+
+	// 🔒 Creator or admin
+	albums := router.Group("/albums")
+	albums.Use(middleware.Authentication())
+	{
+		albums.POST("", controller.CreateAlbum())
+		albums.PATCH("/:album_id", controller.UpdateAlbum())
+		albums.DELETE("/:album_id", controller.DeleteAlbum())
+		albums.PUT("/:album_id/tracks", controller.SetAlbumTracks())
+	}
+}
@@ -10,7 +10,8 @@ func ArtistRoutes(incomingRoutes *gin.Engine) {
 	// Public routes - no authentication required
 	incomingRoutes.GET("/artists", controllers.GetAllArtists())
 	incomingRoutes.GET("/artists/:artist_id", controllers.GetArtistByID())
-	incomingRoutes.GET("/artists/:artist_id/songs", controllers.GetArtistSongs())
+	incomingRoutes.GET("/artists/:artist_id/songs", middleware.OptionalAuthentication(), controllers.GetArtistSongs())
+	incomingRoutes.GET("/artists/:artist_id/related", controllers.GetRelatedArtists())
 
 	// Protected routes - authentication required
 	incomingRoutes.POST("/artists/follow/:artist_id", middleware.Authentication(), controllers.FollowArtist())
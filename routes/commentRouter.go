@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	controller "github.com/ishanbagra18/ecommerce-using-go/controllers"
+	"github.com/ishanbagra18/ecommerce-using-go/middleware"
+)
+
+func CommentRoutes(router *gin.Engine) {
+	router.GET("/song/:song_id/comments", middleware.OptionalAuthentication(), controller.GetSongComments())
+	router.GET("/song/:song_id/comments/:comment_id/replies", middleware.OptionalAuthentication(), controller.GetCommentReplies())
+	router.POST("/song/:song_id/comments", middleware.Authentication(), controller.PostComment())
+
+	comments := router.Group("/comments")
+	comments.Use(middleware.Authentication())
+	{
+		comments.PATCH("/:comment_id", controller.EditComment())
+		comments.DELETE("/:comment_id", controller.DeleteComment())
+		comments.POST("/:comment_id/report", controller.ReportComment())
+	}
+}
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	controller "github.com/ishanbagra18/ecommerce-using-go/controllers"
+	"github.com/ishanbagra18/ecommerce-using-go/middleware"
+)
+
+func GenreRoutes(router *gin.Engine) {
+	// 🌍 Public; genres and moods are managed under /admin/genres
+	router.GET("/genres", controller.GetGenres())
+	router.GET("/genres/:slug", controller.GetGenre())
+	router.GET("/genres/:slug/songs", middleware.OptionalAuthentication(), controller.GetGenreSongs())
+}
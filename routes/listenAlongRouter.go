@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	controller "github.com/ishanbagra18/ecommerce-using-go/controllers"
+	"github.com/ishanbagra18/ecommerce-using-go/middleware"
+)
+
+func ListenAlongRoutes(router *gin.Engine) {
+	router.GET("/listen-along/:session_id/ws", middleware.SocketAuthentication(), controller.ListenSocket())
+
+	listenAlong := router.Group("/listen-along")
+	listenAlong.Use(middleware.Authentication())
+	{
+		listenAlong.POST("", controller.CreateListenSession())
+		listenAlong.POST("/join", controller.JoinListenSession())
+		listenAlong.GET("/:session_id", controller.GetListenSession())
+		listenAlong.DELETE("/:session_id", controller.LeaveListenSession())
+		listenAlong.POST("/:session_id/ticket", controller.CreateListenTicket())
+	}
+}
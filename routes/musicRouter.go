@@ -9,20 +9,28 @@ import (
 func MusicRoute(router *gin.Engine) {
 	// PUBLIC ROUTES (Now with Optional Auth to catch user_id for history)
 	router.GET("/song/:song_id", middleware.OptionalAuthentication(), controller.GetSongByID())
+	router.GET("/song/:song_id/waveform", middleware.OptionalAuthentication(), controller.GetSongWaveform())
+	router.GET("/song/:song_id/lyrics", middleware.OptionalAuthentication(), controller.GetSongLyrics())
+	router.GET("/song/:song_id/similar", middleware.OptionalAuthentication(), controller.GetSimilarSongs())
 
-	router.GET("/allsongs", controller.GetAllSongs)
-	router.GET("/music/searchsong", controller.SearchSongs)
-	router.GET("/music/autocomplete", controller.AutocompleteSearch)
-	router.GET("/music/allsongs", controller.GetAllSongs)
-	router.GET("/music/topsongs", controller.MostLikedSongs())
-	router.GET("/music/saved", controller.MostSavedSongs())
-	router.GET("/music/trendingsongs", controller.TrendingSongs())
+	router.GET("/allsongs", middleware.OptionalAuthentication(), controller.GetAllSongs)
+	router.GET("/music/searchsong", middleware.OptionalAuthentication(), controller.SearchSongs)
+	router.GET("/music/autocomplete", middleware.OptionalAuthentication(), controller.AutocompleteSearch)
+	router.GET("/music/songs", middleware.OptionalAuthentication(), controller.BrowseSongs())
+	router.GET("/music/allsongs", middleware.OptionalAuthentication(), controller.GetAllSongs)
+	router.GET("/music/topsongs", middleware.OptionalAuthentication(), controller.MostLikedSongs())
+	router.GET("/music/saved", middleware.OptionalAuthentication(), controller.MostSavedSongs())
+	router.GET("/music/trendingsongs", middleware.OptionalAuthentication(), controller.TrendingSongs())
 
 	// PROTECTED ROUTES
 	musicGroup := router.Group("/music")
 	musicGroup.Use(middleware.Authentication())
 	{
 		musicGroup.POST("/addsong", controller.UploadSong)
+		musicGroup.PATCH("/:song_id", controller.UpdateSong())
+		musicGroup.DELETE("/:song_id", controller.DeleteSong())
+		musicGroup.PUT("/:song_id/lyrics", controller.SetSongLyrics())
+		musicGroup.DELETE("/:song_id/lyrics", controller.DeleteSongLyrics())
 		musicGroup.GET("/mysongs", controller.MyuploadedSongs())
 		musicGroup.PATCH("/like/:song_id", controller.ToggleLikeSong)
 		musicGroup.PATCH("/save/:song_id", controller.ToggleSave)
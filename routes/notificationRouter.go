@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	controller "github.com/ishanbagra18/ecommerce-using-go/controllers"
+	"github.com/ishanbagra18/ecommerce-using-go/middleware"
+)
+
+func NotificationRoutes(router *gin.Engine) {
+
+	notifications := router.Group("/notifications")
+	notifications.Use(middleware.Authentication())
+	{
+		notifications.GET("/my", controller.GetMyNotifications())
+		notifications.PATCH("/:notification_id/read", controller.MarkNotificationRead())
+		notifications.PATCH("/read-all", controller.MarkAllNotificationsRead())
+	}
+}
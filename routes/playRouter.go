@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	controller "github.com/ishanbagra18/ecommerce-using-go/controllers"
+	"github.com/ishanbagra18/ecommerce-using-go/middleware"
+)
+
+func PlayRoutes(router *gin.Engine) {
+	plays := router.Group("/plays")
+	plays.Use(middleware.Authentication())
+	{
+		plays.POST("/start", controller.StartPlay())
+		plays.POST("/:play_id/progress", controller.UpdatePlayProgress())
+	}
+}
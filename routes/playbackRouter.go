@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	controller "github.com/ishanbagra18/ecommerce-using-go/controllers"
+	"github.com/ishanbagra18/ecommerce-using-go/middleware"
+)
+
+func PlaybackRoutes(router *gin.Engine) {
+	playback := router.Group("/playback")
+	playback.Use(middleware.Authentication())
+	{
+		playback.GET("", controller.GetPlaybackState())
+		playback.PUT("", controller.UpdatePlaybackState())
+	}
+}
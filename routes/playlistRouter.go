@@ -7,8 +7,8 @@ import (
 )
 
 func PlaylistRoute(router *gin.Engine) {
-    // 🌍 Public
-    router.GET("/playlists", controller.GetAllPlaylists())
+    // 🌍 Public, optional auth so listeners who hide explicit songs don't see them
+    router.GET("/playlists", middleware.OptionalAuthentication(), controller.GetAllPlaylists())
 
     // 🔐 Protected
     playlistGroup := router.Group("/playlist")
@@ -17,6 +17,7 @@ func PlaylistRoute(router *gin.Engine) {
         playlistGroup.POST("/create", controller.CreatePlaylist())
         playlistGroup.GET("/playlists", controller.GetAllPlaylists())
         playlistGroup.GET("/myplaylists", controller.GetMyPlaylists())
+        playlistGroup.GET("/mixes", controller.GetMyMixes())
         playlistGroup.GET("/:id", controller.GetPlaylistByID()) // Consider renaming to /:id
         playlistGroup.DELETE("/delete/:id", controller.DeletePlaylist())
         playlistGroup.PUT("/update/:id", controller.UpdatePlaylist())
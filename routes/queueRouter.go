@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	controller "github.com/ishanbagra18/ecommerce-using-go/controllers"
+	"github.com/ishanbagra18/ecommerce-using-go/middleware"
+)
+
+func QueueRoutes(router *gin.Engine) {
+	queue := router.Group("/queue")
+	queue.Use(middleware.Authentication())
+	{
+		queue.GET("", controller.GetQueue())
+		queue.DELETE("", controller.ClearQueue())
+		queue.POST("/play", controller.PlayQueueSource())
+		queue.POST("/add", controller.AddToQueue())
+		queue.POST("/move", controller.MoveQueueItem())
+		queue.POST("/remove", controller.RemoveQueueItem())
+		queue.POST("/next", controller.NextTrack())
+		queue.POST("/previous", controller.PreviousTrack())
+		queue.POST("/shuffle", controller.SetShuffle())
+	}
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	controller "github.com/ishanbagra18/ecommerce-using-go/controllers"
+	"github.com/ishanbagra18/ecommerce-using-go/middleware"
+)
+
+func RadioRoutes(router *gin.Engine) {
+	radio := router.Group("/radio")
+	radio.Use(middleware.Authentication())
+	{
+		radio.POST("", controller.CreateRadioStation())
+		radio.GET("", controller.GetMyRadioStations())
+		radio.GET("/:station_id", controller.GetRadioStation())
+		radio.DELETE("/:station_id", controller.DeleteRadioStation())
+		radio.POST("/:station_id/next", controller.NextRadioTracks())
+		radio.POST("/:station_id/feedback", controller.RadioFeedback())
+	}
+}
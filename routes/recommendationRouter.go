@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	controller "github.com/ishanbagra18/ecommerce-using-go/controllers"
+	"github.com/ishanbagra18/ecommerce-using-go/middleware"
+)
+
+func RecommendationRoutes(router *gin.Engine) {
+	recommendations := router.Group("/recommendations")
+	recommendations.Use(middleware.Authentication())
+	{
+		recommendations.GET("/songs", controller.RecommendedSongs())
+		recommendations.GET("/artists", controller.RecommendedArtists())
+	}
+}
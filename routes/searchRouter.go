@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	controller "github.com/ishanbagra18/ecommerce-using-go/controllers"
+	"github.com/ishanbagra18/ecommerce-using-go/middleware"
+)
+
+func SearchRoutes(router *gin.Engine) {
+	// 🌍 Public, optional auth so uploaders still find their own unreleased songs
+	router.GET("/search", middleware.OptionalAuthentication(), controller.UnifiedSearch())
+	router.GET("/search/songs", middleware.OptionalAuthentication(), controller.FuzzySearchSongs())
+}